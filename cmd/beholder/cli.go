@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -15,7 +16,8 @@ import (
 
 	"github.com/aknow2/beholder/internal/app"
 	"github.com/aknow2/beholder/internal/config"
-	"github.com/aknow2/beholder/internal/summary"
+	"github.com/aknow2/beholder/internal/control"
+	"github.com/aknow2/beholder/internal/pending"
 )
 
 // Version is injected at build time via -X ldflags
@@ -41,6 +43,18 @@ func main() {
 		summaryCmd(args)
 	case "reset":
 		resetCmd(args)
+	case "reclassify":
+		reclassifyCmd(args)
+	case "daemon":
+		daemonCmd(args)
+	case "service":
+		serviceCmd(args)
+	case "db":
+		dbCmd(args)
+	case "pause":
+		pauseCmd(args)
+	case "resume":
+		resumeCmd(args)
 	case "version", "--version", "-v":
 		versionCmd()
 	case "help", "-h", "--help":
@@ -210,7 +224,11 @@ func recordCmd(args []string) {
 			os.Exit(1)
 		}
 
-		fmt.Printf("recorded: id=%s category=%s confidence=%.2f status=%s\n", event.ID, event.CategoryName, event.Confidence, event.Status)
+		if event.ErrorKind != "" {
+			fmt.Printf("recorded: id=%s status=%s error=%s (%s)\n", event.ID, event.Status, event.ErrorKind, event.ErrorMessage)
+			return
+		}
+		fmt.Printf("recorded: id=%s category=%s confidence=%.2f status=%s classified_by=%s\n", event.ID, event.CategoryName, event.Confidence, event.Status, event.ClassifiedBy)
 		return
 	}
 
@@ -235,8 +253,8 @@ func recordCmd(args []string) {
 	}
 }
 
-func eventsCmd(args []string) {
-	fs := flag.NewFlagSet("events", flag.ExitOnError)
+func resetCmd(args []string) {
+	fs := flag.NewFlagSet("reset", flag.ExitOnError)
 	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
 	dateStr := fs.String("date", time.Now().Format("2006-01-02"), "date (YYYY-MM-DD)")
 	_ = fs.Parse(args)
@@ -247,6 +265,19 @@ func eventsCmd(args []string) {
 		os.Exit(1)
 	}
 
+	fmt.Printf("This will delete events for %s. Continue? [y/N]: ", date.Format("2006-01-02"))
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "input error: %v\n", err)
+		os.Exit(1)
+	}
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Println("cancelled")
+		return
+	}
+
 	appInstance, err := app.NewApp(*configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "init error: %v\n", err)
@@ -254,35 +285,20 @@ func eventsCmd(args []string) {
 	}
 	defer appInstance.Close()
 
-	events, err := appInstance.ListEventsByDate(date)
+	deleted, err := appInstance.DeleteEventsByDate(date)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "list error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "delete error: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(events) == 0 {
-		fmt.Println("no events")
-		return
-	}
-
-	for _, e := range events {
-		fmt.Printf("%s | category=%s | confidence=%.2f | status=%s\n", e.CapturedAt.Format(time.RFC3339), e.CategoryName, e.Confidence, e.Status)
-	}
+	fmt.Printf("deleted %d events for %s\n", deleted, date.Format("2006-01-02"))
 }
 
-func summaryCmd(args []string) {
-	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+func reclassifyCmd(args []string) {
+	fs := flag.NewFlagSet("reclassify", flag.ExitOnError)
 	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
-	dateStr := fs.String("date", time.Now().Format("2006-01-02"), "date (YYYY-MM-DD)")
-	format := fs.String("format", "text", "output format: text|markdown")
 	_ = fs.Parse(args)
 
-	date, err := time.ParseInLocation("2006-01-02", *dateStr, time.Local)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid date: %v\n", err)
-		os.Exit(1)
-	}
-
 	appInstance, err := app.NewApp(*configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "init error: %v\n", err)
@@ -290,65 +306,65 @@ func summaryCmd(args []string) {
 	}
 	defer appInstance.Close()
 
-	events, err := appInstance.ListEventsByDate(date)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "list error: %v\n", err)
+	report, err := appInstance.ReclassifyPending(context.Background())
+	if errors.Is(err, pending.ErrLocked) {
+		fmt.Fprintln(os.Stderr, "reclassify error: the daemon is reclassifying the queue, try again later")
 		os.Exit(1)
 	}
-
-	// T022: Remove categoryMap generation, Generate() uses event.CategoryName directly
-	dailySummary := summary.Generate(events)
-
-	switch *format {
-	case "markdown":
-		fmt.Println(dailySummary.FormatMarkdown())
-	case "text":
-		fmt.Println(dailySummary.FormatText())
-	default:
-		fmt.Fprintf(os.Stderr, "unknown format: %s\n", *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reclassify error: %v\n", err)
 		os.Exit(1)
 	}
+
+	fmt.Printf("reclassified %d, still failing %d, abandoned %d\n", report.Reclassified, report.Failed, report.Abandoned)
 }
 
-func resetCmd(args []string) {
-	fs := flag.NewFlagSet("reset", flag.ExitOnError)
-	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
-	dateStr := fs.String("date", time.Now().Format("2006-01-02"), "date (YYYY-MM-DD)")
+func pauseCmd(args []string) {
+	fs := flag.NewFlagSet("pause", flag.ExitOnError)
+	duration := fs.Duration("for", 0, "how long to pause, e.g. 30m (default: until resume)")
 	_ = fs.Parse(args)
 
-	date, err := time.ParseInLocation("2006-01-02", *dateStr, time.Local)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "invalid date: %v\n", err)
+	if *duration < 0 {
+		fmt.Fprintf(os.Stderr, "invalid duration: %v\n", *duration)
 		os.Exit(1)
 	}
 
-	fmt.Printf("This will delete events for %s. Continue? [y/N]: ", date.Format("2006-01-02"))
-	reader := bufio.NewReader(os.Stdin)
-	answer, err := reader.ReadString('\n')
+	pauseFile, err := control.DefaultPauseFile()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "input error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "pause error: %v\n", err)
 		os.Exit(1)
 	}
-	answer = strings.TrimSpace(strings.ToLower(answer))
-	if answer != "y" && answer != "yes" {
-		fmt.Println("cancelled")
+
+	var until time.Time
+	if *duration > 0 {
+		until = time.Now().Add(*duration)
+	}
+	if err := pauseFile.Pause(until); err != nil {
+		fmt.Fprintf(os.Stderr, "pause error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if until.IsZero() {
+		fmt.Println("recording paused until resumed")
 		return
 	}
+	fmt.Printf("recording paused until %s\n", until.Format("2006-01-02 15:04"))
+}
 
-	appInstance, err := app.NewApp(*configPath)
+func resumeCmd(args []string) {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	pauseFile, err := control.DefaultPauseFile()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "init error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "resume error: %v\n", err)
 		os.Exit(1)
 	}
-	defer appInstance.Close()
-
-	deleted, err := appInstance.DeleteEventsByDate(date)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "delete error: %v\n", err)
+	if err := pauseFile.Resume(); err != nil {
+		fmt.Fprintf(os.Stderr, "resume error: %v\n", err)
 		os.Exit(1)
 	}
-
-	fmt.Printf("deleted %d events for %s\n", deleted, date.Format("2006-01-02"))
+	fmt.Println("recording resumed")
 }
 
 func versionCmd() {
@@ -358,14 +374,22 @@ func versionCmd() {
 func printUsage() {
 	fmt.Println("Usage: beholder <command> [options]")
 	fmt.Println("Commands:")
-	fmt.Println("  init     create config interactively")
-	fmt.Println("  record   start scheduled recording (use --oneshot for single capture)")
-	fmt.Println("  events   list events for a date")
-	fmt.Println("  summary  generate daily summary report")
-	fmt.Println("  reset    delete events for a date (requires confirmation)")
-	fmt.Println("  version  display version")
+	fmt.Println("  init        create config interactively")
+	fmt.Println("  record      start scheduled recording (use --oneshot for single capture)")
+	fmt.Println("  events      list events for a date or range (--from/--to, --category, --status, --app, ...)")
+	fmt.Println("  summary     generate a daily, weekly (--week), monthly (--month) or --from/--to report")
+	fmt.Println("  reset       delete events for a date (requires confirmation)")
+	fmt.Println("  reclassify  retry classification of FAILED events kept in the pending queue")
+	fmt.Println("  daemon      run recording in the background: start|stop|restart|status|capture|reload")
+	fmt.Println("  service     install|uninstall a login service (systemd user unit / launchd agent)")
+	fmt.Println("  db          apply database migrations (db migrate --status to inspect)")
+	fmt.Println("  pause       pause recording (use --for 30m for a limited time)")
+	fmt.Println("  resume      resume paused recording")
+	fmt.Println("  version     display version")
 	fmt.Println("Options:")
 	fmt.Println("  --config <path>      config file path (default: ~/.beholder/config.yaml)")
 	fmt.Println("  --date <YYYY-MM-DD>  date for events/summary (default: today)")
-	fmt.Println("  --format <type>      output format for summary: text|markdown (default: text)")
+	fmt.Println("  --format <type>      output format for summary: text|markdown|html (default: text)")
+	fmt.Println("  --template <path>    render summary with a text/template file or built-in name (see --list-templates)")
+	fmt.Println("  --output <type>      output format for events: table|json|ndjson|csv|tsv (default: table)")
 }
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/aknow2/beholder/internal/app"
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/control"
+)
+
+const (
+	daemonStartTimeout = 10 * time.Second
+	daemonStopTimeout  = 30 * time.Second
+	// daemonCallTimeout covers a capture, which classifies synchronously.
+	daemonCallTimeout = 5 * time.Minute
+)
+
+func daemonCmd(args []string) {
+	if len(args) < 1 {
+		printDaemonUsage()
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("daemon "+args[0], flag.ExitOnError)
+	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
+	_ = fs.Parse(args[1:])
+
+	paths, err := control.DefaultPaths()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "start":
+		daemonStart(paths, *configPath)
+	case "stop":
+		daemonStop(paths)
+	case "restart":
+		daemonStop(paths)
+		daemonStart(paths, *configPath)
+	case "status":
+		daemonStatus(paths)
+	case "capture":
+		daemonRequest(paths, control.Request{Command: control.CommandCapture})
+	case "reload":
+		daemonRequest(paths, control.Request{Command: control.CommandReload})
+	case "run":
+		daemonRun(paths, *configPath)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown daemon command: %s\n", args[0])
+		printDaemonUsage()
+		os.Exit(1)
+	}
+}
+
+// daemonRun runs the daemon in the foreground. `daemon start` re-executes
+// the binary with this command, detached from the terminal.
+func daemonRun(paths control.Paths, configPath string) {
+	d, err := app.NewDaemon(configPath, paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := d.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func daemonStart(paths control.Paths, configPath string) {
+	if resp, err := control.Call(paths.Socket, control.Request{Command: control.CommandStatus}, time.Second); err == nil {
+		fmt.Printf("daemon is already running (pid %d)\n", resp.Status.PID)
+		return
+	}
+
+	// The daemon outlives this process and its working directory, so hand
+	// it an absolute config path.
+	resolved, err := config.ResolvePath(configPath)
+	if err == nil {
+		resolved, err = filepath.Abs(resolved)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(filepath.Dir(paths.LogFile), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+	logFile, err := os.OpenFile(paths.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, "daemon", "run", "--config", resolved)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "daemon error: %v\n", err)
+		os.Exit(1)
+	}
+	_ = cmd.Process.Release()
+
+	deadline := time.Now().Add(daemonStartTimeout)
+	for time.Now().Before(deadline) {
+		if resp, err := control.Call(paths.Socket, control.Request{Command: control.CommandStatus}, time.Second); err == nil {
+			fmt.Printf("daemon started (pid %d), log: %s\n", resp.Status.PID, paths.LogFile)
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	fmt.Fprintf(os.Stderr, "daemon did not come up within %v, see %s\n", daemonStartTimeout, paths.LogFile)
+	os.Exit(1)
+}
+
+func daemonStop(paths control.Paths) {
+	_, err := control.Call(paths.Socket, control.Request{Command: control.CommandStop}, 5*time.Second)
+	if errors.Is(err, control.ErrNotRunning) {
+		fmt.Println("daemon is not running")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "stop error: %v\n", err)
+		os.Exit(1)
+	}
+
+	deadline := time.Now().Add(daemonStopTimeout)
+	for time.Now().Before(deadline) {
+		pid, err := control.ReadPID(paths.PIDFile)
+		if err == nil && pid == 0 {
+			fmt.Println("daemon stopped")
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	fmt.Fprintf(os.Stderr, "daemon did not stop within %v\n", daemonStopTimeout)
+	os.Exit(1)
+}
+
+func daemonStatus(paths control.Paths) {
+	resp, err := control.Call(paths.Socket, control.Request{Command: control.CommandStatus}, 5*time.Second)
+	if errors.Is(err, control.ErrNotRunning) {
+		fmt.Println("daemon is not running")
+		if pid, _ := control.ReadPID(paths.PIDFile); pid != 0 {
+			fmt.Printf("stale pid file %s (pid %d)\n", paths.PIDFile, pid)
+		}
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "status error: %v\n", err)
+		os.Exit(1)
+	}
+
+	st := resp.Status
+	fmt.Printf("daemon is running (pid %d)\n", st.PID)
+	fmt.Printf("  started:      %s\n", formatStatusTime(st.StartedAt))
+	fmt.Printf("  config:       %s\n", st.ConfigPath)
+	lastCapture := formatStatusTime(st.LastCapture)
+	switch {
+	case st.LastCategory != "":
+		lastCapture += fmt.Sprintf(" (status=%s category=%s)", st.LastStatus, st.LastCategory)
+	case st.LastStatus != "":
+		lastCapture += fmt.Sprintf(" (status=%s)", st.LastStatus)
+	}
+	fmt.Printf("  last capture: %s\n", lastCapture)
+	lastError := st.LastError
+	if lastError == "" {
+		lastError = "-"
+	}
+	fmt.Printf("  last error:   %s\n", lastError)
+	fmt.Printf("  next capture: %s\n", formatStatusTime(st.NextRun))
+	switch {
+	case !st.Paused:
+		fmt.Println("  paused:       no")
+	case st.PausedUntil.IsZero():
+		fmt.Println("  paused:       until resumed")
+	default:
+		fmt.Printf("  paused:       until %s\n", formatStatusTime(st.PausedUntil))
+	}
+}
+
+func daemonRequest(paths control.Paths, req control.Request) {
+	resp, err := control.Call(paths.Socket, req, daemonCallTimeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s error: %v\n", req.Command, err)
+		os.Exit(1)
+	}
+	if resp.Message != "" {
+		fmt.Println(resp.Message)
+		return
+	}
+	fmt.Println("ok")
+}
+
+func formatStatusTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func printDaemonUsage() {
+	fmt.Println("Usage: beholder daemon <start|stop|restart|status|capture|reload> [--config <path>]")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+func dbCmd(args []string) {
+	if len(args) < 1 || args[0] != "migrate" {
+		fmt.Println("Usage: beholder db migrate [--status] [--config <path>]")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("db migrate", flag.ExitOnError)
+	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
+	status := fs.Bool("status", false, "show applied and pending migrations without applying them")
+	_ = fs.Parse(args[1:])
+
+	// Open the store directly: NewApp would migrate before --status could
+	// report anything.
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
+		os.Exit(1)
+	}
+	store, err := storage.Open(cfg.Storage.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open error: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	if !*status {
+		if err := store.Migrate(cfg.CategoryIDs()); err != nil {
+			fmt.Fprintf(os.Stderr, "migrate error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	states, err := store.MigrationStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "status error: %v\n", err)
+		os.Exit(1)
+	}
+	pending := 0
+	for _, st := range states {
+		applied := "pending"
+		if st.Applied {
+			applied = "applied " + formatStatusTime(st.AppliedAt)
+		} else {
+			pending++
+		}
+		fmt.Printf("%04d %-45s %s\n", st.Version, st.Name, applied)
+	}
+	if *status && pending > 0 {
+		fmt.Printf("%d pending migration(s); run `beholder db migrate` to apply\n", pending)
+	}
+}
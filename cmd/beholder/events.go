@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aknow2/beholder/internal/app"
+	"github.com/aknow2/beholder/internal/export"
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+func eventsCmd(args []string) {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
+	dateStr := fs.String("date", "", "date (YYYY-MM-DD, default: today)")
+	fromStr := fs.String("from", "", "first date of a range (YYYY-MM-DD)")
+	toStr := fs.String("to", "", "last date of a range, inclusive (YYYY-MM-DD)")
+	categories := fs.String("category", "", "comma-separated category ids or names")
+	statuses := fs.String("status", "", "comma-separated statuses, e.g. OK,FAILED")
+	appName := fs.String("app", "", "only events where this app was detected")
+	keyword := fs.String("keyword", "", "only events where this keyword was detected")
+	minConfidence := fs.Float64("min-confidence", 0, "minimum confidence (0-1)")
+	maxConfidence := fs.Float64("max-confidence", 0, "maximum confidence (0-1, 0 for no limit)")
+	limit := fs.Int("limit", 0, "maximum number of events (0 for no limit)")
+	offset := fs.Int("offset", 0, "number of events to skip")
+	order := fs.String("order", storage.OrderAsc, "sort by capture time: asc|desc")
+	output := fs.String("output", export.FormatTable, "output format: "+strings.Join(export.Formats, "|"))
+	_ = fs.Parse(args)
+
+	if !slices.Contains(export.Formats, *output) {
+		fmt.Fprintf(os.Stderr, "unknown output format: %s\n", *output)
+		os.Exit(1)
+	}
+
+	from, to, err := parseDateRange(*dateStr, *fromStr, *toStr, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid date: %v\n", err)
+		os.Exit(1)
+	}
+
+	query := storage.EventQuery{
+		From:          from,
+		To:            to,
+		Categories:    splitList(*categories),
+		Statuses:      splitList(*statuses),
+		MinConfidence: *minConfidence,
+		MaxConfidence: *maxConfidence,
+		App:           *appName,
+		Keyword:       *keyword,
+		Limit:         *limit,
+		Offset:        *offset,
+		Order:         *order,
+	}
+
+	appInstance, err := app.NewApp(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init error: %v\n", err)
+		os.Exit(1)
+	}
+	defer appInstance.Close()
+
+	events, err := appInstance.ListEvents(query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := export.Write(os.Stdout, *output, events); err != nil {
+		fmt.Fprintf(os.Stderr, "output error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseDateRange turns the --date or --from/--to flags into a half-open
+// range of local time. A missing end of the range defaults to the other end,
+// and no flags at all mean the day containing now.
+func parseDateRange(date, from, to string, now time.Time) (time.Time, time.Time, error) {
+	if date != "" && (from != "" || to != "") {
+		return time.Time{}, time.Time{}, fmt.Errorf("--date cannot be combined with --from/--to")
+	}
+	if date != "" {
+		from, to = date, date
+	}
+	if from == "" && to == "" {
+		from = now.Format("2006-01-02")
+	}
+	if from == "" {
+		from = to
+	}
+	if to == "" {
+		to = from
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", from, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := time.ParseInLocation("2006-01-02", to, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to %s is before --from %s", to, from)
+	}
+	start, end = storage.DateRange(start, end)
+	return start, end, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
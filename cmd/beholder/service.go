@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/control"
+	"github.com/aknow2/beholder/internal/service"
+)
+
+func serviceCmd(args []string) {
+	if len(args) < 1 || (args[0] != "install" && args[0] != "uninstall") {
+		fmt.Println("Usage: beholder service <install|uninstall> [--config <path>] [--print]")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("service "+args[0], flag.ExitOnError)
+	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
+	printOnly := fs.Bool("print", false, "print the unit file instead of installing it")
+	_ = fs.Parse(args[1:])
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+		os.Exit(1)
+	}
+	manager, err := service.New(runtime.GOOS, homeDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if args[0] == "uninstall" {
+		if err := manager.Uninstall(); err != nil {
+			fmt.Fprintf(os.Stderr, "uninstall error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("removed %s service %s\n", manager.Name(), manager.Path())
+		return
+	}
+
+	spec, err := serviceSpec(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *printOnly {
+		data, err := manager.Render(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "service error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(string(data))
+		return
+	}
+
+	if err := manager.Install(spec); err != nil {
+		fmt.Fprintf(os.Stderr, "install error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("installed %s service %s\n", manager.Name(), manager.Path())
+}
+
+// serviceSpec points the service at this binary and an absolute config
+// path, since the service manager starts it from a different directory.
+func serviceSpec(configPath string) (service.Spec, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return service.Spec{}, err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return service.Spec{}, err
+	}
+
+	resolved, err := config.ResolvePath(configPath)
+	if err != nil {
+		return service.Spec{}, err
+	}
+	if resolved, err = filepath.Abs(resolved); err != nil {
+		return service.Spec{}, err
+	}
+
+	paths, err := control.DefaultPaths()
+	if err != nil {
+		return service.Spec{}, err
+	}
+	return service.Spec{Binary: exe, ConfigPath: resolved, LogFile: paths.LogFile}, nil
+}
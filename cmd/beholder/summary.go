@@ -0,0 +1,182 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aknow2/beholder/internal/app"
+	"github.com/aknow2/beholder/internal/scheduler"
+	"github.com/aknow2/beholder/internal/storage"
+	"github.com/aknow2/beholder/internal/summary"
+)
+
+func summaryCmd(args []string) {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	configPath := fs.String("config", "~/.beholder/config.yaml", "path to config file")
+	dateStr := fs.String("date", "", "date (YYYY-MM-DD, default: today)")
+	week := fs.String("week", "", "ISO week for a weekly report (YYYY-Www)")
+	month := fs.String("month", "", "month for a monthly report (YYYY-MM)")
+	fromStr := fs.String("from", "", "first date of a custom range (YYYY-MM-DD)")
+	toStr := fs.String("to", "", "last date of a custom range, inclusive (YYYY-MM-DD)")
+	format := fs.String("format", "text", "output format: text|markdown|html")
+	out := fs.String("out", "", "write the report to this file instead of stdout")
+	templateRef := fs.String("template", "", "render with a text/template file or a built-in template name (overrides --format)")
+	listTemplates := fs.Bool("list-templates", false, "list the built-in templates and exit")
+	_ = fs.Parse(args)
+
+	if *listTemplates {
+		for _, name := range summary.BuiltinTemplates() {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	var tmpl *template.Template
+	if *templateRef != "" {
+		var err error
+		if tmpl, err = summary.LoadTemplate(*templateRef); err != nil {
+			fmt.Fprintf(os.Stderr, "template error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	period, isRange, err := summaryPeriod(*dateStr, *week, *month, *fromStr, *toStr, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid period: %v\n", err)
+		os.Exit(1)
+	}
+	if *format != "text" && *format != "markdown" && *format != "html" {
+		fmt.Fprintf(os.Stderr, "unknown format: %s\n", *format)
+		os.Exit(1)
+	}
+
+	appInstance, err := app.NewApp(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init error: %v\n", err)
+		os.Exit(1)
+	}
+	defer appInstance.Close()
+
+	opts := summary.Options{
+		MaxSpan: scheduler.MaxInterval(appInstance.Config.Scheduler),
+	}
+
+	events, err := periodEvents(appInstance.ListEvents, period, opts.MaxSpan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var report formatter
+	var data *summary.TemplateData
+	if isRange {
+		previousPeriod := period.Previous()
+		previous, err := periodEvents(appInstance.ListEvents, previousPeriod, opts.MaxSpan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "list error: %v\n", err)
+			os.Exit(1)
+		}
+		rangeSummary := summary.GenerateRange(period, events, previous, opts)
+		report, data = rangeSummary, summary.RangeTemplateData(rangeSummary, appInstance.Config)
+	} else {
+		dailySummary := summary.GenerateWithOptions(period, events, opts)
+		report, data = dailySummary, summary.DailyTemplateData(period, dailySummary, appInstance.Config)
+	}
+
+	var text string
+	switch {
+	case tmpl != nil:
+		var sb strings.Builder
+		if err := summary.ExecuteTemplate(&sb, tmpl, data); err != nil {
+			fmt.Fprintf(os.Stderr, "template error: %v\n", err)
+			os.Exit(1)
+		}
+		text = sb.String()
+	case *format == "markdown":
+		text = report.FormatMarkdown() + "\n"
+	case *format == "html":
+		text, err = report.FormatHTML(summary.CategoryColors(appInstance.Config.Categories))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "render error: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		text = report.FormatText() + "\n"
+	}
+
+	if *out == "" {
+		fmt.Print(text)
+		return
+	}
+	if err := os.WriteFile(*out, []byte(text), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "write error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %s\n", *out)
+}
+
+// periodEvents lists the events that can count towards p: those captured in
+// it, the ones captured up to maxSpan before it, and lock and sleep spans
+// that started earlier still. The summary clips them to p.
+func periodEvents(list func(storage.EventQuery) ([]storage.Event, error), p summary.Period, maxSpan time.Duration) ([]storage.Event, error) {
+	if maxSpan <= 0 {
+		maxSpan = summary.DefaultMaxSpan
+	}
+	from := p.Start.Add(-maxSpan)
+	events, err := list(storage.EventQuery{From: from, To: p.End})
+	if err != nil {
+		return nil, err
+	}
+	spans, err := list(storage.EventQuery{To: from, SpanEndAfter: p.Start})
+	if err != nil {
+		return nil, err
+	}
+	return append(spans, events...), nil
+}
+
+// formatter is implemented by both daily and range summaries.
+type formatter interface {
+	FormatText() string
+	FormatMarkdown() string
+	FormatHTML(colors map[string]string) (string, error)
+}
+
+// summaryPeriod picks the period from the summary flags, at most one of
+// --date, --week, --month and --from/--to. isRange is false for a single
+// day report.
+func summaryPeriod(date, week, month, from, to string, now time.Time) (period summary.Period, isRange bool, err error) {
+	set := 0
+	for _, v := range []string{date, week, month, from + to} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return summary.Period{}, false, fmt.Errorf("use only one of --date, --week, --month and --from/--to")
+	}
+
+	switch {
+	case week != "":
+		period, err = summary.WeekPeriod(week, now.Location())
+		return period, true, err
+	case month != "":
+		period, err = summary.MonthPeriod(month, now.Location())
+		return period, true, err
+	case from != "" || to != "":
+		start, end, err := parseDateRange("", from, to, now)
+		if err != nil {
+			return summary.Period{}, false, err
+		}
+		return summary.DaysPeriod(start, end.AddDate(0, 0, -1)), true, nil
+	default:
+		start, end, err := parseDateRange(date, "", "", now)
+		if err != nil {
+			return summary.Period{}, false, err
+		}
+		return summary.DaysPeriod(start, end.AddDate(0, 0, -1)), false, nil
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+	"github.com/aknow2/beholder/internal/summary"
+)
+
+func TestPeriodEventsAcrossMidnight(t *testing.T) {
+	store, err := storage.Open(filepath.Join(t.TempDir(), "beholder.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 1, day, hour, min, 0, 0, time.Local) }
+	events := []*storage.Event{
+		// Runs 5m into the 28th.
+		{ID: "late", CapturedAt: at(27, 23, 55), CategoryID: "implement", CategoryName: "実装", Status: "OK"},
+		{ID: "morning", CapturedAt: at(28, 9, 0), CategoryID: "implement", CategoryName: "実装", Status: "OK"},
+		// Suspended from before the 29th until 07:00 on it.
+		{ID: "suspend", CapturedAt: at(28, 22, 0), CategoryID: "afk", CategoryName: "離席", Status: "SUSPENDED", SpanEnd: at(29, 7, 0)},
+	}
+	for _, e := range events {
+		e.CreatedAt = e.CapturedAt
+		if err := store.InsertEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		day  int
+		want map[string]time.Duration
+	}{
+		{28, map[string]time.Duration{"実装": 15 * time.Minute, "離席": 2 * time.Hour}},
+		{29, map[string]time.Duration{"離席": 7 * time.Hour}},
+	}
+	for _, tt := range tests {
+		day := summary.DaysPeriod(at(tt.day, 0, 0), at(tt.day, 0, 0))
+		list, err := periodEvents(store.ListEvents, day, 10*time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := summary.GenerateWithOptions(day, list, summary.Options{MaxSpan: 10 * time.Minute})
+		got := map[string]time.Duration{}
+		for _, c := range s.Categories {
+			got[c.CategoryName] = c.Duration
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("day %d: categories %v, want %v", tt.day, got, tt.want)
+		}
+		for name, d := range tt.want {
+			if got[name] != d {
+				t.Errorf("day %d: %s = %v, want %v", tt.day, name, got[name], d)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package activewin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+)
+
+// ErrUnsupported is returned by sources that cannot query the focused window
+// on the current platform or session.
+var ErrUnsupported = errors.New("active window lookup is not supported here")
+
+// Info describes the window that currently has input focus.
+type Info struct {
+	Title   string
+	Process string
+	PID     int
+	// URL is the page shown in a browser window when the source can tell.
+	URL string
+}
+
+type Source interface {
+	Active(ctx context.Context) (*Info, error)
+}
+
+// New returns the best Source for the current session.
+func New() Source {
+	if runtime.GOOS != "darwin" && runtime.GOOS != "windows" && os.Getenv("DISPLAY") != "" {
+		return X11{}
+	}
+	return unsupported{}
+}
+
+// Static always reports the same window. It is meant for tests.
+type Static struct {
+	Info *Info
+	Err  error
+}
+
+func (s Static) Active(ctx context.Context) (*Info, error) {
+	return s.Info, s.Err
+}
+
+type unsupported struct{}
+
+func (unsupported) Active(ctx context.Context) (*Info, error) {
+	return nil, ErrUnsupported
+}
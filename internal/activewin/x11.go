@@ -0,0 +1,76 @@
+package activewin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// X11 reads the EWMH _NET_ACTIVE_WINDOW hint from the root window, which
+// every mainstream window manager maintains.
+type X11 struct{}
+
+func (X11) Active(ctx context.Context) (*Info, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("connect to X server: %w", err)
+	}
+	defer conn.Close()
+
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	prop, err := getProperty(conn, root, "_NET_ACTIVE_WINDOW")
+	if err != nil {
+		return nil, err
+	}
+	if len(prop.Value) < 4 {
+		return nil, fmt.Errorf("_NET_ACTIVE_WINDOW not set")
+	}
+	win := xproto.Window(xgb.Get32(prop.Value))
+	if win == 0 {
+		return nil, fmt.Errorf("no window has focus")
+	}
+
+	info := &Info{}
+	if p, err := getProperty(conn, win, "_NET_WM_NAME"); err == nil && len(p.Value) > 0 {
+		info.Title = string(p.Value)
+	} else if p, err := getProperty(conn, win, "WM_NAME"); err == nil {
+		info.Title = string(p.Value)
+	}
+	if p, err := getProperty(conn, win, "_NET_WM_PID"); err == nil && len(p.Value) >= 4 {
+		info.PID = int(xgb.Get32(p.Value))
+		info.Process = processName(info.PID)
+	}
+	if info.Process == "" {
+		// WM_CLASS is "instance\x00class\x00"; the class is a decent
+		// stand-in when the PID is unknown, e.g. for remote clients.
+		if p, err := getProperty(conn, win, "WM_CLASS"); err == nil {
+			parts := strings.Split(strings.TrimRight(string(p.Value), "\x00"), "\x00")
+			info.Process = parts[len(parts)-1]
+		}
+	}
+	return info, nil
+}
+
+func getProperty(conn *xgb.Conn, win xproto.Window, name string) (*xproto.GetPropertyReply, error) {
+	atom, err := xproto.InternAtom(conn, true, uint16(len(name)), name).Reply()
+	if err != nil {
+		return nil, err
+	}
+	if atom.Atom == xproto.AtomNone {
+		return nil, fmt.Errorf("atom %s not found", name)
+	}
+	return xproto.GetProperty(conn, false, win, atom.Atom, xproto.GetPropertyTypeAny, 0, 1<<16).Reply()
+}
+
+func processName(pid int) string {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/comm")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
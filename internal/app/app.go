@@ -1,32 +1,91 @@
 package app
 
 import (
+	"io"
+
+	"github.com/aknow2/beholder/internal/activewin"
+	"github.com/aknow2/beholder/internal/capture"
 	"github.com/aknow2/beholder/internal/classify"
 	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/control"
+	"github.com/aknow2/beholder/internal/idle"
+	"github.com/aknow2/beholder/internal/pending"
+	"github.com/aknow2/beholder/internal/power"
+	"github.com/aknow2/beholder/internal/scheduler"
 	"github.com/aknow2/beholder/internal/storage"
 )
 
 type App struct {
 	Config     *config.Config
 	Storage    *storage.Store
-	Classifier *classify.Client
+	Classifier classify.Classifier
+	// Provider is the model-backed classifier without window rules, used
+	// for deferred reclassification where the focused window is long gone.
+	Provider classify.Classifier
+	Capturer capture.Capturer
+	Pending  *pending.Queue
+	Idle     idle.Source
+	Power    power.Source
+	Pause    control.PauseFile
+
+	// Set by runScheduler and only touched on the scheduler goroutine.
+	strategy     scheduler.Strategy
+	calendar     *scheduler.Calendar
+	reclassifier *scheduler.Scheduler
 }
 
 func NewApp(configPath string) (*App, error) {
-	cfg, err := config.Load(configPath)
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return newApp(cfg)
+}
+
+func loadConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
 	if err != nil {
 		return nil, err
 	}
 	if err := config.Validate(cfg); err != nil {
 		return nil, err
 	}
+	return cfg, nil
+}
+
+func newApp(cfg *config.Config) (_ *App, err error) {
+	capturer, err := capture.New(cfg.Capture)
+	if err != nil {
+		return nil, err
+	}
+
+	classifier, provider, err := newClassifiers(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// The provider may run a client process, don't leave it behind.
+	defer func() {
+		if c, ok := provider.(io.Closer); ok && err != nil {
+			_ = c.Close()
+		}
+	}()
+
+	queue, err := openPendingQueue()
+	if err != nil {
+		return nil, err
+	}
+
+	pauseFile, err := control.DefaultPauseFile()
+	if err != nil {
+		return nil, err
+	}
 
 	store, err := storage.Open(cfg.Storage.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := store.Migrate(); err != nil {
+	if err := store.Migrate(cfg.CategoryIDs()); err != nil {
 		_ = store.Close()
 		return nil, err
 	}
@@ -36,12 +95,42 @@ func NewApp(configPath string) (*App, error) {
 	return &App{
 		Config:     cfg,
 		Storage:    store,
-		Classifier: classify.NewClient(cfg.Copilot.Model),
+		Classifier: classifier,
+		Provider:   provider,
+		Capturer:   capturer,
+		Pending:    queue,
+		Idle:       idle.New(),
+		Power:      power.New(),
+		Pause:      pauseFile,
 	}, nil
 }
 
+// newClassifiers builds the classifier used for captures and the provider
+// alone, see App.Provider.
+func newClassifiers(cfg *config.Config) (classifier, provider classify.Classifier, err error) {
+	p, err := classify.New(cfg.Classifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	retrying := classify.NewRetrying(p, cfg.Classifier)
+	rules, err := classify.NewRules(activewin.New(), retrying, cfg.Categories)
+	if err != nil {
+		if c, ok := p.(io.Closer); ok {
+			_ = c.Close()
+		}
+		return nil, nil, err
+	}
+	return rules, retrying, nil
+}
+
 func (a *App) Close() {
-	if a != nil && a.Storage != nil {
+	if a == nil {
+		return
+	}
+	if c, ok := a.Provider.(io.Closer); ok {
+		_ = c.Close()
+	}
+	if a.Storage != nil {
 		_ = a.Storage.Close()
 	}
 }
@@ -1,32 +1,42 @@
 package app
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"image"
-	_ "image/jpeg"
-	_ "image/png"
-	"io"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/aknow2/beholder/internal/capture"
+	"github.com/aknow2/beholder/internal/classify"
 	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/imaging"
+	"github.com/aknow2/beholder/internal/storage"
 )
 
 type CaptureResult struct {
-	PNG          []byte
+	Data         []byte
 	DisplayCount int
+	Displays     []storage.DisplayInfo
 	Resolution   string
-	ImagePath    string
+	Images       []CapturedImage
 	CleanupImage bool
 }
 
-func captureFullScreenPNG(cfg *config.Config) (*CaptureResult, error) {
+// CapturedImage is one encoded file handed to the classifier. In composite
+// mode there is a single image covering every display.
+type CapturedImage struct {
+	Path        string
+	Description string
+	Width       int
+	Height      int
+}
+
+func captureScreenshots(ctx context.Context, capturer capture.Capturer, cfg *config.Config) (*CaptureResult, error) {
 	cleanupImage := false
 
 	homeDir, err := os.UserHomeDir()
@@ -43,8 +53,6 @@ func captureFullScreenPNG(cfg *config.Config) (*CaptureResult, error) {
 		cleanupImage = true
 	}
 
-	tmpDir := os.TempDir()
-	rawPath := filepath.Join(tmpDir, fmt.Sprintf("beholder-raw-%d.png", time.Now().UnixNano()))
 	timestamp := time.Now().Format("20060102-150405")
 
 	// T013: Select format based on config
@@ -54,44 +62,78 @@ func captureFullScreenPNG(cfg *config.Config) (*CaptureResult, error) {
 		ext = "png"
 		formatArg = "png"
 	}
-	resizedPath := filepath.Join(imgDir, fmt.Sprintf("screenshot-%s.%s", timestamp, ext))
-
-	cmd := exec.Command("screencapture", "-x", "-t", "png", rawPath)
-	if err := cmd.Run(); err != nil {
-		return nil, err
-	}
-	defer os.Remove(rawPath)
 
-	// T012: Use Config.Image.MaxWidth dynamically
-	maxWidthStr := fmt.Sprintf("%d", cfg.Image.MaxWidth)
-	resizeCmd := exec.Command("sips", "-s", "format", formatArg, "-Z", maxWidthStr, rawPath, "--out", resizedPath)
-	if err := resizeCmd.Run(); err != nil {
-		return nil, fmt.Errorf("resize failed: %w", err)
+	displays, err := capturer.Capture(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("capture (%s): %w", capturer.Name(), err)
 	}
 
-	info, err := os.Stat(resizedPath)
-	if err != nil {
-		return nil, err
+	result := &CaptureResult{
+		DisplayCount: len(displays),
+		CleanupImage: cleanupImage,
 	}
-	if info.Size() > 3*1024*1024 {
-		return nil, fmt.Errorf("image too large after resize: %d bytes", info.Size())
+	for _, d := range displays {
+		size := d.Image.Bounds().Size()
+		result.Displays = append(result.Displays, storage.DisplayInfo{
+			Index:   d.Index,
+			X:       d.Bounds.Min.X,
+			Y:       d.Bounds.Min.Y,
+			Width:   size.X,
+			Height:  size.Y,
+			Primary: d.Primary,
+			Focused: d.Focused,
+		})
 	}
 
-	f, err := os.Open(resizedPath)
-	if err != nil {
-		return nil, err
+	type pending struct {
+		name        string
+		description string
+		img         image.Image
 	}
-	defer f.Close()
-
-	data, err := io.ReadAll(f)
-	if err != nil {
-		return nil, err
+	var toEncode []pending
+	if cfg.Capture.DisplayMode == capture.DisplayModeSeparate && len(displays) > 1 {
+		for _, d := range displays {
+			toEncode = append(toEncode, pending{
+				name:        fmt.Sprintf("screenshot-%s-d%d.%s", timestamp, d.Index+1, ext),
+				description: describeDisplay(d, len(displays)),
+				img:         d.Image,
+			})
+		}
+	} else {
+		toEncode = append(toEncode, pending{
+			name:        fmt.Sprintf("screenshot-%s.%s", timestamp, ext),
+			description: describeComposite(displays),
+			img:         capture.Composite(displays),
+		})
 	}
 
-	cfg2, _, err := image.DecodeConfig(bytes.NewReader(data))
-	if err != nil {
-		return nil, err
+	var resolutions []string
+	for _, p := range toEncode {
+		encoded, err := imaging.Process(p.img, imaging.Options{
+			MaxWidth: cfg.Image.MaxWidth,
+			Format:   formatArg,
+			Quality:  cfg.Image.Quality,
+		})
+		if err != nil {
+			result.removeImages()
+			return nil, err
+		}
+		path := filepath.Join(imgDir, p.name)
+		if err := os.WriteFile(path, encoded.Data, 0644); err != nil {
+			_ = os.Remove(path)
+			result.removeImages()
+			return nil, err
+		}
+		result.Data = append(result.Data, encoded.Data...)
+		result.Images = append(result.Images, CapturedImage{
+			Path:        path,
+			Description: p.description,
+			Width:       encoded.Width,
+			Height:      encoded.Height,
+		})
+		resolutions = append(resolutions, fmt.Sprintf("%dx%d", encoded.Width, encoded.Height))
 	}
+	result.Resolution = strings.Join(resolutions, ",")
 
 	// T015-T017: Cleanup old images if max_files is set
 	if cfg.Image.SaveImages && cfg.Image.MaxFiles > 0 {
@@ -101,13 +143,46 @@ func captureFullScreenPNG(cfg *config.Config) (*CaptureResult, error) {
 		}
 	}
 
-	return &CaptureResult{
-		PNG:          data,
-		DisplayCount: 1,
-		Resolution:   fmt.Sprintf("%dx%d", cfg2.Width, cfg2.Height),
-		ImagePath:    resizedPath,
-		CleanupImage: cleanupImage,
-	}, nil
+	return result, nil
+}
+
+func (r *CaptureResult) removeImages() {
+	for _, img := range r.Images {
+		_ = os.Remove(img.Path)
+	}
+}
+
+func (r *CaptureResult) screenshots() []classify.Screenshot {
+	shots := make([]classify.Screenshot, 0, len(r.Images))
+	for _, img := range r.Images {
+		shots = append(shots, classify.Screenshot{Path: img.Path, Description: img.Description})
+	}
+	return shots
+}
+
+func describeDisplay(d capture.Display, total int) string {
+	size := d.Image.Bounds().Size()
+	desc := fmt.Sprintf("Display %d of %d (%dx%d)", d.Index+1, total, size.X, size.Y)
+	if d.Primary {
+		desc += ", primary"
+	}
+	if d.Focused {
+		desc += ", focused"
+	}
+	return desc
+}
+
+func describeComposite(displays []capture.Display) string {
+	if len(displays) == 1 {
+		return "the only display"
+	}
+	desc := fmt.Sprintf("%d displays stitched by desktop layout, each labeled in its top-left corner", len(displays))
+	for _, d := range displays {
+		if d.Focused {
+			desc += fmt.Sprintf("; Display %d has focus and is outlined in red", d.Index+1)
+		}
+	}
+	return desc
 }
 
 // T015-T016: Cleanup old images based on max_files setting
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/control"
+	"github.com/aknow2/beholder/internal/scheduler"
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+// configPollInterval is how often the daemon checks the config file for
+// changes.
+const configPollInterval = 2 * time.Second
+
+// Daemon runs the scheduler in the background and answers requests on the
+// control socket. Reloading the config applies it to the running App and
+// scheduler between captures.
+type Daemon struct {
+	configPath string
+	paths      control.Paths
+	pause      control.PauseFile
+	startedAt  time.Time
+
+	app      *App
+	ctx      context.Context
+	stop     context.CancelFunc
+	reloadCh chan chan error
+
+	mu          sync.Mutex
+	sched       *scheduler.Scheduler
+	record      scheduler.RecordFunc
+	lastEvent   *storage.Event
+	lastCapture time.Time
+	lastError   string
+}
+
+func NewDaemon(configPath string, paths control.Paths) (*Daemon, error) {
+	a, err := NewApp(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Daemon{
+		configPath: configPath,
+		paths:      paths,
+		pause:      a.Pause,
+		app:        a,
+		reloadCh:   make(chan chan error),
+	}, nil
+}
+
+// Run writes the PID file, serves the control socket and records on
+// schedule until ctx is cancelled or a stop request arrives.
+func (d *Daemon) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	d.ctx, d.stop = ctx, cancel
+	d.startedAt = time.Now()
+	defer d.app.Close()
+
+	// Claim the socket before the PID file, so a second daemon gives up
+	// without touching the running one's files.
+	listener, err := control.Listen(d.paths.Socket)
+	if err != nil {
+		return fmt.Errorf("control socket: %w", err)
+	}
+
+	pid := os.Getpid()
+	if err := control.WritePID(d.paths.PIDFile, pid); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("write pid file: %w", err)
+	}
+	defer func() {
+		if err := control.RemovePID(d.paths.PIDFile, pid); err != nil {
+			log.Printf("Warning: failed to remove pid file: %v", err)
+		}
+	}()
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- control.Serve(ctx, listener, d) }()
+	go d.watchConfig(ctx)
+	go d.reloadOnHangup(ctx)
+	log.Printf("daemon started (pid %d), control socket %s", pid, d.paths.Socket)
+
+	done := make(chan error, 1)
+	go func() { done <- d.app.runScheduler(ctx, d.hooks()) }()
+
+	for {
+		select {
+		case <-ctx.Done():
+			<-done
+			return nil
+		case err := <-done:
+			return err
+		case err := <-serveErr:
+			if err != nil {
+				err = fmt.Errorf("control socket: %w", err)
+			}
+			cancel()
+			<-done
+			return err
+		case reply := <-d.reloadCh:
+			err := d.reload(ctx)
+			if err != nil {
+				log.Printf("config reload failed, keeping the current config: %v", err)
+			}
+			reply <- err
+		}
+	}
+}
+
+// reload loads and validates the config file and applies it between
+// captures. The scheduler keeps running, so its timer, lock and sleep state
+// and adaptive history survive; a config that fails to load or build
+// leaves everything as it was.
+func (d *Daemon) reload(ctx context.Context) error {
+	d.mu.Lock()
+	s := d.sched
+	d.mu.Unlock()
+	if s == nil {
+		return errors.New("scheduler is not running")
+	}
+
+	cfg, err := loadConfig(d.configPath)
+	if err != nil {
+		return err
+	}
+	u, err := d.app.prepareUpdate(cfg)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		log.Println("config reload: no changes")
+		return nil
+	}
+	if err := s.Do(ctx, func(ctx context.Context) { d.app.apply(ctx, s, u) }); err != nil {
+		if c, ok := u.provider.(io.Closer); ok {
+			_ = c.Close()
+		}
+		return err
+	}
+	log.Printf("config reloaded from %s", d.configPath)
+	return nil
+}
+
+// watchConfig polls the config file and reloads when it changes. Editors
+// often replace the file rather than write to it, which polling handles
+// without special cases.
+func (d *Daemon) watchConfig(ctx context.Context) {
+	path, err := config.ResolvePath(d.configPath)
+	if err != nil {
+		log.Printf("Warning: not watching config: %v", err)
+		return
+	}
+	stamp := func() string {
+		info, err := os.Stat(path)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+	}
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	last := stamp()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := stamp()
+			if current == last || current == "" {
+				continue
+			}
+			last = current
+			log.Printf("config file changed, reloading")
+			_ = d.Reload()
+		}
+	}
+}
+
+func (d *Daemon) reloadOnHangup(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Println("SIGHUP received, reloading config")
+			_ = d.Reload()
+		}
+	}
+}
+
+func (d *Daemon) hooks() schedulerHooks {
+	return schedulerHooks{
+		started: func(s *scheduler.Scheduler, record scheduler.RecordFunc) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.sched = s
+			d.record = record
+		},
+		recorded: func(event *storage.Event, err error) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.lastCapture = time.Now()
+			d.lastEvent = event
+			switch {
+			case err != nil:
+				d.lastError = err.Error()
+			case event.ErrorMessage != "":
+				d.lastError = event.ErrorMessage
+			default:
+				d.lastError = ""
+			}
+		},
+	}
+}
+
+func (d *Daemon) Status() control.Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	st := control.Status{
+		PID:         os.Getpid(),
+		StartedAt:   d.startedAt,
+		ConfigPath:  d.configPath,
+		LastCapture: d.lastCapture,
+		LastError:   d.lastError,
+	}
+	if d.lastEvent != nil {
+		st.LastStatus = d.lastEvent.Status
+		st.LastCategory = d.lastEvent.CategoryName
+	}
+	if d.sched != nil {
+		st.NextRun = d.sched.Next()
+	}
+	if until, paused, err := d.pause.PausedUntil(time.Now()); err == nil {
+		st.Paused = paused
+		st.PausedUntil = until
+	}
+	return st
+}
+
+// Capture records an event right away, regardless of schedule and pauses.
+func (d *Daemon) Capture(ctx context.Context) (string, error) {
+	d.mu.Lock()
+	s, record := d.sched, d.record
+	d.mu.Unlock()
+	if s == nil {
+		return "", errors.New("scheduler is not running")
+	}
+
+	var recordErr error
+	if err := s.Do(ctx, func(ctx context.Context) { recordErr = record(ctx) }); err != nil {
+		return "", err
+	}
+	if recordErr != nil {
+		return "", recordErr
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e := d.lastEvent
+	if e.ErrorKind != "" {
+		return fmt.Sprintf("recorded %s: status=%s error=%s", e.ID, e.Status, e.ErrorKind), nil
+	}
+	return fmt.Sprintf("recorded %s: status=%s category=%s", e.ID, e.Status, e.CategoryName), nil
+}
+
+func (d *Daemon) Pause(dur time.Duration) error {
+	var until time.Time
+	if dur > 0 {
+		until = time.Now().Add(dur)
+	}
+	return d.pause.Pause(until)
+}
+
+func (d *Daemon) Resume() error {
+	return d.pause.Resume()
+}
+
+// Reload re-reads the config file. An invalid config is reported and the
+// daemon keeps running with the old one.
+func (d *Daemon) Reload() error {
+	reply := make(chan error, 1)
+	select {
+	case d.reloadCh <- reply:
+	case <-d.ctx.Done():
+		return scheduler.ErrStopped
+	}
+	return <-reply
+}
+
+func (d *Daemon) Stop() {
+	log.Println("stop requested over control socket")
+	d.stop()
+}
@@ -2,26 +2,133 @@ package app
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/pending"
 	"github.com/aknow2/beholder/internal/scheduler"
+	"github.com/aknow2/beholder/internal/storage"
 )
 
+// schedulerHooks let a Daemon follow the scheduler started by
+// runScheduler. Either func may be nil.
+type schedulerHooks struct {
+	started  func(s *scheduler.Scheduler, record scheduler.RecordFunc)
+	recorded func(event *storage.Event, err error)
+}
+
+// StartScheduler records on schedule until ctx is cancelled.
 func (a *App) StartScheduler(ctx context.Context) error {
-	if a.Config.Scheduler.IntervalMinutes <= 0 {
-		log.Println("scheduler interval not configured, using default 10 minutes")
-		a.Config.Scheduler.IntervalMinutes = 10
+	return a.runScheduler(ctx, schedulerHooks{})
+}
+
+func (a *App) runScheduler(ctx context.Context, hooks schedulerHooks) error {
+	strategy, calendar, err := newSchedule(a.Config)
+	if err != nil {
+		return err
 	}
+	a.strategy, a.calendar = strategy, calendar
 
 	recordFunc := func(ctx context.Context) error {
-		_, err := a.RecordOnce(ctx)
-		return err
+		event, err := a.RecordOnce(ctx)
+		if hooks.recorded != nil {
+			hooks.recorded(event, err)
+		}
+		if err != nil {
+			return err
+		}
+		// a.strategy changes on reload, so look it up on every run.
+		// Category ids, unlike names, survive a rename on reload.
+		if observer, ok := a.strategy.(scheduler.Observer); ok && event.CategoryID != "" {
+			observer.Observe(event.CategoryID)
+		}
+		return nil
 	}
 
-	s := scheduler.New(a.Config.Scheduler.IntervalMinutes, recordFunc)
+	s := scheduler.NewWithStrategy(strategy, recordFunc)
+	s.SkipWhen(func(now time.Time) bool {
+		return a.paused(now) || !a.calendar.Allows(now)
+	})
+	if a.Config.Scheduler.PauseOnLock && a.Power != nil {
+		signals, err := a.Power.Subscribe(ctx)
+		if err != nil {
+			log.Printf("Warning: lock and sleep signals unavailable, capturing regardless: %v", err)
+		} else {
+			s.WatchPower(signals, a.recordSpan)
+		}
+	}
+
+	a.startReclassify(ctx)
+	defer a.stopReclassify()
 
-	log.Printf("starting scheduler with %d minute interval", a.Config.Scheduler.IntervalMinutes)
+	if hooks.started != nil {
+		hooks.started(s, recordFunc)
+	}
+	log.Printf("starting scheduler: %v", strategy)
 	s.Start(ctx)
 
 	return nil
 }
+
+// newSchedule builds the strategy and calendar for cfg.
+func newSchedule(cfg *config.Config) (scheduler.Strategy, *scheduler.Calendar, error) {
+	strategy, err := scheduler.NewStrategy(cfg.Scheduler)
+	if err != nil {
+		return nil, nil, err
+	}
+	calendar, err := scheduler.NewCalendar(cfg.Schedule, time.Local)
+	if err != nil {
+		return nil, nil, err
+	}
+	return strategy, calendar, nil
+}
+
+// startReclassify retries the pending queue in the background while
+// reclassify is enabled, until stopReclassify.
+func (a *App) startReclassify(ctx context.Context) {
+	if !a.Config.Reclassify.Enabled {
+		return
+	}
+	reclassifyFunc := func(ctx context.Context) error {
+		report, err := a.ReclassifyPending(ctx)
+		if errors.Is(err, pending.ErrLocked) {
+			log.Println("reclassify: skipped, `beholder reclassify` is running")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if report.Reclassified+report.Failed+report.Abandoned > 0 {
+			log.Printf("reclassify: %d reclassified, %d still failing, %d abandoned", report.Reclassified, report.Failed, report.Abandoned)
+		}
+		return nil
+	}
+	a.reclassifier = scheduler.New(a.Config.Reclassify.IntervalMinutes, reclassifyFunc)
+	go a.reclassifier.Start(ctx)
+	log.Printf("starting reclassification every %d minutes", a.Config.Reclassify.IntervalMinutes)
+}
+
+// stopReclassify stops the background reclassification, waiting for a run
+// in progress to finish.
+func (a *App) stopReclassify() {
+	if a.reclassifier != nil {
+		a.reclassifier.Stop()
+		a.reclassifier = nil
+	}
+}
+
+// paused reports whether `beholder pause` is in effect. An unreadable pause
+// file is logged and ignored so a stray file cannot stop recording for good.
+func (a *App) paused(now time.Time) bool {
+	if a.Pause == "" {
+		return false
+	}
+	_, paused, err := a.Pause.PausedUntil(now)
+	if err != nil {
+		log.Printf("Warning: failed to read pause file: %v", err)
+		return false
+	}
+	return paused
+}
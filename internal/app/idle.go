@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aknow2/beholder/internal/idle"
+	"github.com/aknow2/beholder/internal/storage"
+	"github.com/google/uuid"
+)
+
+// classifiedByIdle marks events recorded from the idle time alone.
+const classifiedByIdle = "idle"
+
+// idleFor reports how long the user has been idle and whether that is past
+// the configured threshold. Lookup failures count as active so capture
+// carries on as before.
+func (a *App) idleFor(ctx context.Context) (time.Duration, bool) {
+	if !a.Config.Idle.Enabled || a.Idle == nil {
+		return 0, false
+	}
+	d, err := a.Idle.Idle(ctx)
+	if err != nil {
+		if !errors.Is(err, idle.ErrUnsupported) {
+			log.Printf("Warning: failed to read idle time: %v", err)
+		}
+		return 0, false
+	}
+	return d, d >= time.Duration(a.Config.Idle.ThresholdMinutes)*time.Minute
+}
+
+// recordIdle inserts an IDLE event in the configured idle category without
+// taking a screenshot.
+func (a *App) recordIdle(idleFor time.Duration) (*storage.Event, error) {
+	now := time.Now().UTC()
+	event := &storage.Event{
+		ID:           uuid.NewString(),
+		CapturedAt:   now,
+		Status:       "IDLE",
+		CategoryID:   a.Config.Idle.CategoryID,
+		CategoryName: a.categoryName(a.Config.Idle.CategoryID),
+		Confidence:   1,
+		ClassifiedBy: classifiedByIdle,
+		IdleSeconds:  int(idleFor.Seconds()),
+		CreatedAt:    now,
+	}
+	if err := a.Storage.InsertEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (a *App) categoryName(id string) string {
+	for _, cat := range a.Config.Categories {
+		if cat.ID == id {
+			return cat.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/aknow2/beholder/internal/scheduler"
+	"github.com/aknow2/beholder/internal/storage"
+	"github.com/google/uuid"
+)
+
+// classifiedBySession marks events recorded from lock and sleep signals.
+const classifiedBySession = "session"
+
+// recordSpan inserts a SUSPENDED or LOCKED event covering span, filed under
+// the idle category, as IDLE events are, so away time shows up in summaries
+// whether or not idle detection is on. Validate requires that category
+// whenever pause_on_lock is set.
+func (a *App) recordSpan(ctx context.Context, span scheduler.Span) error {
+	event := &storage.Event{
+		ID:           uuid.NewString(),
+		CapturedAt:   span.Start.UTC(),
+		Status:       span.Kind,
+		CategoryID:   a.Config.Idle.CategoryID,
+		CategoryName: a.categoryName(a.Config.Idle.CategoryID),
+		Confidence:   1,
+		ClassifiedBy: classifiedBySession,
+		SpanEnd:      span.End.UTC(),
+		CreatedAt:    time.Now().UTC(),
+	}
+	return a.Storage.InsertEvent(event)
+}
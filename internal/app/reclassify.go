@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/aknow2/beholder/internal/classify"
+	"github.com/aknow2/beholder/internal/pending"
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+type ReclassifyReport struct {
+	Reclassified int
+	Failed       int
+	Abandoned    int
+}
+
+func openPendingQueue() (*pending.Queue, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return pending.Open(filepath.Join(homeDir, ".beholder", "pending"))
+}
+
+// ReclassifyPending retries classification for every event in the pending
+// queue and updates the stored events in place. Entries that succeed, or
+// that reach reclassify.max_attempts, leave the queue. It fails with
+// pending.ErrLocked while another process, e.g. the daemon, is at it.
+func (a *App) ReclassifyPending(ctx context.Context) (*ReclassifyReport, error) {
+	unlock, err := a.Pending.Lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := a.Pending.List()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReclassifyReport{}
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		screenshots := make([]classify.Screenshot, 0, len(entry.Images))
+		for _, img := range entry.Images {
+			screenshots = append(screenshots, classify.Screenshot{Path: img.Path, Description: img.Description})
+		}
+
+		classification, err := a.Provider.Classify(ctx, screenshots, a.Config.Categories)
+		if err == nil {
+			classification.Source = classify.SourceLLM
+		}
+		entry.Attempts++
+
+		event := &storage.Event{
+			ID:               entry.EventID,
+			ClassifyAttempts: entry.Attempts,
+			DisplayCount:     entry.DisplayCount,
+		}
+		a.applyClassification(event, classification, err, entry.Resolution)
+		if uerr := a.Storage.UpdateClassification(event); uerr != nil {
+			// The event was deleted (e.g. by reset); nothing left to fix.
+			log.Printf("reclassify %s: %v, dropping from queue", entry.EventID, uerr)
+			_ = a.Pending.Remove(entry.EventID)
+			continue
+		}
+
+		switch {
+		case err == nil:
+			report.Reclassified++
+			if rerr := a.Pending.Remove(entry.EventID); rerr != nil {
+				log.Printf("Warning: failed to remove %s from queue: %v", entry.EventID, rerr)
+			}
+		case entry.Attempts >= a.Config.Reclassify.MaxAttempts:
+			report.Abandoned++
+			log.Printf("reclassify %s: giving up after %d attempts", entry.EventID, entry.Attempts)
+			_ = a.Pending.Remove(entry.EventID)
+		default:
+			report.Failed++
+			entry.LastError = err.Error()
+			if uerr := a.Pending.Update(entry); uerr != nil {
+				log.Printf("Warning: failed to update queue entry %s: %v", entry.EventID, uerr)
+			}
+		}
+	}
+	return report, nil
+}
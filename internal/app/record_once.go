@@ -4,86 +4,110 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
 	"log"
-	"os"
 	"time"
 
+	"github.com/aknow2/beholder/internal/classify"
+	"github.com/aknow2/beholder/internal/pending"
 	"github.com/aknow2/beholder/internal/storage"
 	"github.com/google/uuid"
 )
 
 func (a *App) RecordOnce(ctx context.Context) (*storage.Event, error) {
-	captureResult, err := captureFullScreenPNG(a.Config)
-	if err != nil {
-		return nil, err
+	if idleFor, ok := a.idleFor(ctx); ok {
+		return a.recordIdle(idleFor)
 	}
-	if captureResult.CleanupImage && captureResult.ImagePath != "" {
-		defer func(path string) {
-			_ = os.Remove(path)
-		}(captureResult.ImagePath)
-	}
-
-	classification, err := a.Classifier.Classify(ctx, captureResult.ImagePath, a.Config.Categories)
-
-	status := "OK"
-	categoryID := ""
-	categoryName := ""
-	confidence := 0.0
-	rationale := ""
-	var detectedApps []string
-	var detectedKeywords []string
 
+	captureResult, err := captureScreenshots(ctx, a.Capturer, a.Config)
 	if err != nil {
-		log.Printf("classification failed: %v", err)
-		status = "FAILED"
-	} else {
-		categoryID = classification.SelectedCategoryID
-		confidence = classification.Confidence
-		rationale = classification.Rationale
-		detectedApps = classification.DetectedApps
-		detectedKeywords = classification.DetectedKeywords
+		return nil, err
 	}
-
-	if categoryID == "" && len(a.Config.Categories) > 0 {
-		categoryID = a.Config.Categories[0].ID
+	if captureResult.CleanupImage {
+		defer captureResult.removeImages()
 	}
 
-	// T020: Map category ID to Name from Config
-	for _, cat := range a.Config.Categories {
-		if cat.ID == categoryID {
-			categoryName = cat.Name
-			break
-		}
-	}
+	screenshots := captureResult.screenshots()
+	classification, classifyErr := a.Classifier.Classify(ctx, screenshots, a.Config.Categories)
 
-	hash := sha256.Sum256(captureResult.PNG)
+	hash := sha256.Sum256(captureResult.Data)
 	screenshotHash := hex.EncodeToString(hash[:])
 
 	event := &storage.Event{
 		ID:               uuid.NewString(),
 		CapturedAt:       time.Now().UTC(),
-		CategoryName:     categoryName,
-		Confidence:       confidence,
-		Status:           status,
-		AgentVersion:     a.Config.Copilot.Model,
 		ScreenshotHash:   screenshotHash,
-		DetectedApps:     detectedApps,
-		DetectedKeywords: detectedKeywords,
-		Notes:            fmt.Sprintf("rationale=%s displayCount=%d resolution=%s", rationale, captureResult.DisplayCount, captureResult.Resolution),
+		ClassifyAttempts: 1,
+		DisplayCount:     captureResult.DisplayCount,
+		Displays:         captureResult.Displays,
 		CreatedAt:        time.Now().UTC(),
 	}
+	a.applyClassification(event, classification, classifyErr, captureResult.Resolution)
 
 	if err := a.Storage.InsertEvent(event); err != nil {
 		return nil, err
 	}
+
+	if classifyErr != nil && a.Config.Reclassify.Enabled {
+		entry := &pending.Entry{
+			EventID:      event.ID,
+			CapturedAt:   event.CapturedAt,
+			DisplayCount: captureResult.DisplayCount,
+			Resolution:   captureResult.Resolution,
+			Attempts:     1,
+			LastError:    event.ErrorMessage,
+		}
+		for _, s := range screenshots {
+			entry.Images = append(entry.Images, pending.Image{Path: s.Path, Description: s.Description})
+		}
+		if qerr := a.Pending.Add(entry); qerr != nil {
+			log.Printf("Warning: failed to queue event %s for reclassification: %v", event.ID, qerr)
+		}
+	}
 	return event, nil
 }
 
+// applyClassification fills the classification fields of event from the
+// classifier's result or error.
+func (a *App) applyClassification(event *storage.Event, classification *classify.Result, err error, resolution string) {
+	event.AgentVersion = a.Classifier.Model()
+	event.ClassifierProvider = a.Classifier.Name()
+	event.Resolution = resolution
+
+	if err != nil {
+		log.Printf("classification failed: %v", err)
+		event.Status = "FAILED"
+		event.CategoryID = ""
+		event.CategoryName = ""
+		event.Confidence = 0
+		event.ClassifiedBy = ""
+		event.ErrorKind = string(classify.KindOf(err))
+		event.ErrorMessage = err.Error()
+		event.DetectedApps = nil
+		event.DetectedKeywords = nil
+		event.Rationale = ""
+	} else {
+		event.Status = "OK"
+		// T020: Map category ID to Name from Config
+		event.CategoryID = classification.SelectedCategoryID
+		event.CategoryName = a.categoryName(classification.SelectedCategoryID)
+		event.Confidence = classification.Confidence
+		event.ClassifiedBy = classification.Source
+		event.ErrorKind = ""
+		event.ErrorMessage = ""
+		event.DetectedApps = classification.DetectedApps
+		event.DetectedKeywords = classification.DetectedKeywords
+		event.Rationale = classification.Rationale
+	}
+}
+
 func (a *App) ListEventsByDate(date time.Time) ([]storage.Event, error) {
 	return a.Storage.ListEventsByDate(date)
 }
 
+func (a *App) ListEvents(q storage.EventQuery) ([]storage.Event, error) {
+	return a.Storage.ListEvents(q)
+}
+
 func (a *App) DeleteEventsByDate(date time.Time) (int64, error) {
 	return a.Storage.DeleteEventsByDate(date)
 }
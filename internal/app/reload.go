@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/aknow2/beholder/internal/capture"
+	"github.com/aknow2/beholder/internal/classify"
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/scheduler"
+)
+
+// configUpdate is a reloaded config together with everything built from it
+// that can fail, so applying it cannot fail halfway.
+type configUpdate struct {
+	cfg        *config.Config
+	classifier classify.Classifier
+	provider   classify.Classifier
+	capturer   capture.Capturer   // nil when capture settings are unchanged
+	strategy   scheduler.Strategy // nil when scheduler settings are unchanged
+	calendar   *scheduler.Calendar
+}
+
+// prepareUpdate checks cfg against the running config and builds what
+// applying it needs. It returns nil when nothing changed.
+func (a *App) prepareUpdate(cfg *config.Config) (*configUpdate, error) {
+	strategy, calendar, err := newSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := config.Diff(a.Config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if cfg.Storage.Path != a.Config.Storage.Path {
+		return nil, fmt.Errorf("storage.path cannot change while running, restart the daemon instead")
+	}
+	if cfg.Scheduler.PauseOnLock != a.Config.Scheduler.PauseOnLock {
+		return nil, fmt.Errorf("scheduler.pause_on_lock cannot change while running, restart the daemon instead")
+	}
+	for _, c := range changes {
+		log.Printf("config reload: %s", c)
+	}
+
+	u := &configUpdate{cfg: cfg, calendar: calendar}
+	if u.classifier, u.provider, err = newClassifiers(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Capture != a.Config.Capture {
+		if u.capturer, err = capture.New(cfg.Capture); err != nil {
+			return nil, err
+		}
+	}
+	// Keep the running strategy, and with it the adaptive history, unless
+	// its settings changed.
+	if cfg.Scheduler != a.Config.Scheduler {
+		u.strategy = strategy
+	}
+	return u, nil
+}
+
+// apply swaps u into the App. It must run on the scheduler goroutine, see
+// scheduler.Scheduler.Do, so no capture sees half of it.
+func (a *App) apply(ctx context.Context, s *scheduler.Scheduler, u *configUpdate) {
+	a.stopReclassify()
+	if c, ok := a.Provider.(io.Closer); ok {
+		_ = c.Close()
+	}
+
+	a.Config = u.cfg
+	a.Classifier, a.Provider = u.classifier, u.provider
+	if u.capturer != nil {
+		a.Capturer = u.capturer
+	}
+	if u.strategy != nil {
+		a.strategy = u.strategy
+		s.SetStrategy(u.strategy)
+		log.Printf("scheduler: %v from the next run", u.strategy)
+	}
+	a.calendar = u.calendar
+
+	a.startReclassify(ctx)
+}
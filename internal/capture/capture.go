@@ -0,0 +1,122 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+const (
+	BackendAuto          = "auto"
+	BackendScreencapture = "screencapture"
+	BackendX11           = "x11"
+	BackendWayland       = "wayland"
+	BackendPortal        = "portal"
+	BackendGDI           = "gdi"
+	BackendFake          = "fake"
+)
+
+const (
+	DisplayModeComposite = "composite"
+	DisplayModeSeparate  = "separate"
+)
+
+// Display is one monitor's worth of pixels. Bounds is the monitor's position
+// within the virtual desktop, so displays can be laid out relative to each
+// other.
+type Display struct {
+	Index   int
+	Bounds  image.Rectangle
+	Primary bool
+	Focused bool
+	Image   image.Image
+}
+
+// Capturer grabs the current contents of every attached display. Backends
+// that cannot tell monitors apart return a single Display covering the
+// whole desktop.
+type Capturer interface {
+	Name() string
+	Capture(ctx context.Context) ([]Display, error)
+}
+
+// New returns the Capturer selected by cfg.Backend. An empty backend or
+// "auto" picks one based on the running platform.
+func New(cfg config.CaptureConfig) (Capturer, error) {
+	backend := cfg.Backend
+	if backend == "" || backend == BackendAuto {
+		backend = detectBackend()
+	}
+
+	switch backend {
+	case BackendScreencapture:
+		return &screencaptureCapturer{}, nil
+	case BackendX11:
+		return &x11Capturer{}, nil
+	case BackendWayland:
+		return &waylandCapturer{}, nil
+	case BackendPortal:
+		return &portalCapturer{}, nil
+	case BackendGDI:
+		return &gdiCapturer{}, nil
+	case BackendFake:
+		return NewFake(cfg.FakeDir)
+	default:
+		return nil, fmt.Errorf("unknown capture backend: %s", backend)
+	}
+}
+
+// markFocused flags the display containing p, falling back to the primary
+// display (or the first one) when p is outside every display.
+func markFocused(displays []Display, p image.Point) {
+	for i := range displays {
+		if p.In(displays[i].Bounds) {
+			displays[i].Focused = true
+			return
+		}
+	}
+	for i := range displays {
+		if displays[i].Primary {
+			displays[i].Focused = true
+			return
+		}
+	}
+	if len(displays) > 0 {
+		displays[0].Focused = true
+	}
+}
+
+func detectBackend() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return BackendScreencapture
+	case "windows":
+		return BackendGDI
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		_, err := exec.LookPath("grim")
+		return waylandBackend(os.Getenv("XDG_CURRENT_DESKTOP"), err == nil)
+	}
+	return BackendX11
+}
+
+// waylandBackend picks grim on wlroots based compositors and the desktop
+// portal elsewhere. GNOME and KDE do not let grim capture the screen.
+func waylandBackend(desktop string, hasGrim bool) string {
+	if !hasGrim {
+		return BackendPortal
+	}
+	for _, d := range strings.Split(desktop, ":") {
+		switch strings.ToUpper(d) {
+		case "GNOME", "KDE":
+			return BackendPortal
+		}
+	}
+	return BackendWayland
+}
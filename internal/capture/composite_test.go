@@ -0,0 +1,42 @@
+package capture
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b, a := c.RGBA()
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+	}
+	return img
+}
+
+func TestCompositeLayout(t *testing.T) {
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	green := color.RGBA{0, 0xff, 0, 0xff}
+	displays := []Display{
+		{Index: 0, Bounds: image.Rect(0, 0, 400, 300), Primary: true, Image: solid(400, 300, blue)},
+		{Index: 1, Bounds: image.Rect(400, 0, 1000, 400), Focused: true, Image: solid(600, 400, green)},
+	}
+
+	img := Composite(displays)
+	if got := img.Bounds().Size(); got != image.Pt(1000, 400) {
+		t.Fatalf("size = %v, want 1000x400", got)
+	}
+	if got := color.RGBAModel.Convert(img.At(200, 200)); got != blue {
+		t.Errorf("display 1 pixel = %v, want %v", got, blue)
+	}
+	if got := color.RGBAModel.Convert(img.At(700, 200)); got != green {
+		t.Errorf("display 2 pixel = %v, want %v", got, green)
+	}
+	if got := color.RGBAModel.Convert(img.At(999, 200)); got != focusColor {
+		t.Errorf("focused display border = %v, want %v", got, focusColor)
+	}
+	if got := color.RGBAModel.Convert(img.At(200, 350)); got != (color.RGBA{}) {
+		t.Errorf("area outside displays = %v, want transparent", got)
+	}
+}
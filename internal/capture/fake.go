@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fake replays image files from a directory in name order, wrapping around
+// after the last one. It lets the record pipeline run without a display.
+type Fake struct {
+	dir   string
+	files []string
+
+	mu   sync.Mutex
+	next int
+}
+
+func NewFake(dir string) (*Fake, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("fake capture backend requires capture.fake_dir")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".png", ".jpg", ".jpeg":
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no images found in %s", dir)
+	}
+	sort.Strings(files)
+
+	return &Fake{dir: dir, files: files}, nil
+}
+
+func (f *Fake) Name() string {
+	return BackendFake
+}
+
+func (f *Fake) Capture(ctx context.Context) ([]Display, error) {
+	f.mu.Lock()
+	path := f.files[f.next]
+	f.next = (f.next + 1) % len(f.files)
+	f.mu.Unlock()
+
+	img, err := decodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return []Display{{Index: 0, Bounds: img.Bounds(), Primary: true, Focused: true, Image: img}}, nil
+}
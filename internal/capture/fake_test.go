@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFakeCyclesImages(t *testing.T) {
+	dir := t.TempDir()
+	writePNG(t, filepath.Join(dir, "a.png"), 10, 10)
+	writePNG(t, filepath.Join(dir, "b.png"), 20, 10)
+
+	c, err := New(config.CaptureConfig{Backend: BackendFake, FakeDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{10, 20, 10}
+	for i, w := range want {
+		displays, err := c.Capture(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(displays) != 1 || !displays[0].Focused {
+			t.Fatalf("capture %d: want one focused display, got %+v", i, displays)
+		}
+		if got := displays[0].Image.Bounds().Dx(); got != w {
+			t.Errorf("capture %d: width = %d, want %d", i, got, w)
+		}
+	}
+}
+
+func TestFakeEmptyDir(t *testing.T) {
+	if _, err := NewFake(t.TempDir()); err == nil {
+		t.Error("empty dir should error")
+	}
+}
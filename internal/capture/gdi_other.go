@@ -0,0 +1,18 @@
+//go:build !windows
+
+package capture
+
+import (
+	"context"
+	"fmt"
+)
+
+type gdiCapturer struct{}
+
+func (c *gdiCapturer) Name() string {
+	return BackendGDI
+}
+
+func (c *gdiCapturer) Capture(ctx context.Context) ([]Display, error) {
+	return nil, fmt.Errorf("gdi capture backend is only available on windows")
+}
@@ -0,0 +1,124 @@
+//go:build windows
+
+package capture
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"unsafe"
+
+	"github.com/lxn/win"
+	"golang.org/x/sys/windows"
+)
+
+var procEnumDisplayMonitors = windows.NewLazySystemDLL("user32.dll").NewProc("EnumDisplayMonitors")
+
+// gdiCapturer copies each monitor with BitBlt.
+type gdiCapturer struct{}
+
+func (c *gdiCapturer) Name() string {
+	return BackendGDI
+}
+
+func (c *gdiCapturer) Capture(ctx context.Context) ([]Display, error) {
+	displays, err := gdiMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range displays {
+		img, err := grabGDI(displays[i].Bounds)
+		if err != nil {
+			return nil, fmt.Errorf("display %d: %w", displays[i].Index, err)
+		}
+		displays[i].Image = img
+	}
+
+	var cursor win.POINT
+	pointer := image.Pt(-1, -1)
+	if win.GetCursorPos(&cursor) {
+		pointer = image.Pt(int(cursor.X), int(cursor.Y))
+	}
+	markFocused(displays, pointer)
+	return displays, nil
+}
+
+func gdiMonitors() ([]Display, error) {
+	var displays []Display
+	callback := windows.NewCallback(func(hMonitor win.HMONITOR, hdc win.HDC, rect *win.RECT, lParam uintptr) uintptr {
+		var info win.MONITORINFO
+		info.CbSize = uint32(unsafe.Sizeof(info))
+		if !win.GetMonitorInfo(hMonitor, &info) {
+			return 1
+		}
+		r := info.RcMonitor
+		displays = append(displays, Display{
+			Index:   len(displays),
+			Bounds:  image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom)),
+			Primary: info.DwFlags&win.MONITORINFOF_PRIMARY != 0,
+		})
+		return 1
+	})
+
+	ret, _, err := procEnumDisplayMonitors.Call(0, 0, callback, 0)
+	if ret == 0 {
+		return nil, fmt.Errorf("EnumDisplayMonitors failed: %w", err)
+	}
+	if len(displays) == 0 {
+		return nil, fmt.Errorf("no monitors found")
+	}
+	return displays, nil
+}
+
+func grabGDI(rect image.Rectangle) (*image.RGBA, error) {
+	width, height := int32(rect.Dx()), int32(rect.Dy())
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid capture area: %v", rect)
+	}
+
+	screenDC := win.GetDC(0)
+	if screenDC == 0 {
+		return nil, fmt.Errorf("GetDC failed")
+	}
+	defer win.ReleaseDC(0, screenDC)
+
+	memDC := win.CreateCompatibleDC(screenDC)
+	if memDC == 0 {
+		return nil, fmt.Errorf("CreateCompatibleDC failed")
+	}
+	defer win.DeleteDC(memDC)
+
+	bitmap := win.CreateCompatibleBitmap(screenDC, width, height)
+	if bitmap == 0 {
+		return nil, fmt.Errorf("CreateCompatibleBitmap failed")
+	}
+	defer win.DeleteObject(win.HGDIOBJ(bitmap))
+
+	old := win.SelectObject(memDC, win.HGDIOBJ(bitmap))
+	defer win.SelectObject(memDC, old)
+
+	if !win.BitBlt(memDC, 0, 0, width, height, screenDC, int32(rect.Min.X), int32(rect.Min.Y), win.SRCCOPY|win.CAPTUREBLT) {
+		return nil, fmt.Errorf("BitBlt failed")
+	}
+
+	var bmi win.BITMAPINFO
+	bmi.BmiHeader.BiSize = uint32(unsafe.Sizeof(bmi.BmiHeader))
+	bmi.BmiHeader.BiWidth = width
+	bmi.BmiHeader.BiHeight = -height // top-down rows
+	bmi.BmiHeader.BiPlanes = 1
+	bmi.BmiHeader.BiBitCount = 32
+	bmi.BmiHeader.BiCompression = win.BI_RGB
+
+	img := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	if win.GetDIBits(memDC, bitmap, 0, uint32(height), &img.Pix[0], &bmi, win.DIB_RGB_COLORS) == 0 {
+		return nil, fmt.Errorf("GetDIBits failed")
+	}
+
+	// GDI returns BGRA; swap to RGBA and force opaque alpha.
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+		img.Pix[i+3] = 0xff
+	}
+	return img, nil
+}
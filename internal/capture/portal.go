@@ -0,0 +1,125 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	portalDest       = "org.freedesktop.portal.Desktop"
+	portalPath       = "/org/freedesktop/portal/desktop"
+	portalRequest    = "org.freedesktop.portal.Request"
+	portalResponse   = portalRequest + ".Response"
+	portalScreenshot = "org.freedesktop.portal.Screenshot.Screenshot"
+)
+
+// portalTokens makes each request's handle_token unique within the process.
+var portalTokens atomic.Uint64
+
+// portalCapturer asks the desktop through the xdg-desktop-portal Screenshot
+// API, which GNOME and KDE implement where grim cannot capture. The portal
+// saves the screenshot to a file, which is read and removed. The desktop may
+// ask the user once to allow screenshots; the whole layout is one display.
+type portalCapturer struct{}
+
+func (c *portalCapturer) Name() string {
+	return BackendPortal
+}
+
+func (c *portalCapturer) Capture(ctx context.Context) ([]Display, error) {
+	conn, err := dbus.ConnectSessionBus(dbus.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("connect session bus: %w", err)
+	}
+	defer conn.Close()
+
+	// Subscribe to the response before asking, on the request path the
+	// portal derives from our bus name and token.
+	token := fmt.Sprintf("beholder%d", portalTokens.Add(1))
+	sender := strings.ReplaceAll(strings.TrimPrefix(conn.Names()[0], ":"), ".", "_")
+	handle := dbus.ObjectPath(portalPath + "/request/" + sender + "/" + token)
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(handle),
+		dbus.WithMatchInterface(portalRequest),
+		dbus.WithMatchMember("Response"),
+	); err != nil {
+		return nil, err
+	}
+	signals := make(chan *dbus.Signal, 1)
+	conn.Signal(signals)
+
+	options := map[string]dbus.Variant{
+		"handle_token": dbus.MakeVariant(token),
+		"interactive":  dbus.MakeVariant(false),
+	}
+	if err := conn.Object(portalDest, portalPath).CallWithContext(ctx, portalScreenshot, 0, "", options).Store(&handle); err != nil {
+		return nil, fmt.Errorf("portal screenshot: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case sig, ok := <-signals:
+			if !ok {
+				return nil, fmt.Errorf("portal screenshot: session bus closed")
+			}
+			if sig.Path != handle || sig.Name != portalResponse {
+				continue
+			}
+			path, err := portalResult(sig)
+			if err != nil {
+				return nil, err
+			}
+			img, err := readScreenshot(path)
+			if err != nil {
+				return nil, err
+			}
+			return []Display{{Index: 0, Bounds: img.Bounds(), Primary: true, Focused: true, Image: img}}, nil
+		}
+	}
+}
+
+// portalResult reads the file path from a Request.Response signal, whose
+// body is the response code (0 on success) and a results map with "uri".
+func portalResult(sig *dbus.Signal) (string, error) {
+	if len(sig.Body) != 2 {
+		return "", fmt.Errorf("portal screenshot: unexpected response %v", sig.Body)
+	}
+	switch code, _ := sig.Body[0].(uint32); code {
+	case 0:
+	case 1:
+		return "", fmt.Errorf("portal screenshot: denied or cancelled by the user")
+	default:
+		return "", fmt.Errorf("portal screenshot: failed with response %d", code)
+	}
+	results, _ := sig.Body[1].(map[string]dbus.Variant)
+	uri, _ := results["uri"].Value().(string)
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", fmt.Errorf("portal screenshot: unexpected uri %q", uri)
+	}
+	return u.Path, nil
+}
+
+// readScreenshot decodes the file the portal wrote and removes it.
+func readScreenshot(path string) (image.Image, error) {
+	defer os.Remove(path)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode portal screenshot: %w", err)
+	}
+	return img, nil
+}
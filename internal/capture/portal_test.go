@@ -0,0 +1,40 @@
+package capture
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestPortalResult(t *testing.T) {
+	response := func(code uint32, uri string) *dbus.Signal {
+		return &dbus.Signal{Name: portalResponse, Body: []interface{}{code, map[string]dbus.Variant{"uri": dbus.MakeVariant(uri)}}}
+	}
+	if got, err := portalResult(response(0, "file:///home/alice/Pictures/Screenshot%20from%202026.png")); err != nil || got != "/home/alice/Pictures/Screenshot from 2026.png" {
+		t.Errorf("success = %q, %v", got, err)
+	}
+	for _, sig := range []*dbus.Signal{response(1, ""), response(2, ""), response(0, "https://example.com/a.png"), {Name: portalResponse}} {
+		if _, err := portalResult(sig); err == nil {
+			t.Errorf("portalResult(%v) should fail", sig.Body)
+		}
+	}
+}
+
+func TestWaylandBackend(t *testing.T) {
+	tests := []struct {
+		desktop string
+		hasGrim bool
+		want    string
+	}{
+		{"sway", true, BackendWayland},
+		{"Hyprland", true, BackendWayland},
+		{"ubuntu:GNOME", true, BackendPortal},
+		{"KDE", true, BackendPortal},
+		{"sway", false, BackendPortal},
+	}
+	for _, tt := range tests {
+		if got := waylandBackend(tt.desktop, tt.hasGrim); got != tt.want {
+			t.Errorf("waylandBackend(%q, %v) = %s, want %s", tt.desktop, tt.hasGrim, got, tt.want)
+		}
+	}
+}
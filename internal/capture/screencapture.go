@@ -0,0 +1,115 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScreencaptureDisplays is how many output files we hand screencapture.
+// It writes one file per attached display and ignores the rest.
+const maxScreencaptureDisplays = 8
+
+// screencaptureCapturer uses the macOS screencapture command.
+type screencaptureCapturer struct{}
+
+func (c *screencaptureCapturer) Name() string {
+	return BackendScreencapture
+}
+
+func (c *screencaptureCapturer) Capture(ctx context.Context) ([]Display, error) {
+	stamp := time.Now().UnixNano()
+	paths := make([]string, maxScreencaptureDisplays)
+	for i := range paths {
+		paths[i] = filepath.Join(os.TempDir(), fmt.Sprintf("beholder-raw-%d-%d.png", stamp, i))
+	}
+	defer func() {
+		for _, p := range paths {
+			_ = os.Remove(p)
+		}
+	}()
+
+	args := append([]string{"-x", "-t", "png"}, paths...)
+	cmd := exec.CommandContext(ctx, "screencapture", args...)
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("screencapture failed: %w", err)
+	}
+
+	// screencapture does not report display geometry, so displays are laid
+	// out left to right in the order it wrote them. The first is the main
+	// display.
+	var displays []Display
+	x := 0
+	for i, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			break
+		}
+		img, err := decodeFile(p)
+		if err != nil {
+			return nil, err
+		}
+		size := img.Bounds().Size()
+		displays = append(displays, Display{
+			Index:   i,
+			Bounds:  image.Rect(x, 0, x+size.X, size.Y),
+			Primary: i == 0,
+			Image:   img,
+		})
+		x += size.X
+	}
+	if len(displays) == 0 {
+		return nil, fmt.Errorf("screencapture produced no images")
+	}
+	focus := image.Pt(-1, -1)
+	if i := focusedScreen(ctx); i >= 0 && i < len(displays) {
+		focus = displays[i].Bounds.Min
+	}
+	markFocused(displays, focus)
+	return displays, nil
+}
+
+// focusedScreenScript prints the index in NSScreen.screens of the screen
+// under the mouse, or -1. That list starts with the main display like
+// screencapture's output does.
+const focusedScreenScript = `ObjC.import("AppKit");
+var p = $.NSEvent.mouseLocation, screens = $.NSScreen.screens, found = -1;
+for (var i = 0; i < screens.count; i++) {
+	var f = screens.objectAtIndex(i).frame;
+	if (p.x >= f.origin.x && p.x < f.origin.x + f.size.width && p.y >= f.origin.y && p.y < f.origin.y + f.size.height) { found = i; break; }
+}
+found;`
+
+// focusedScreen returns the index of the display under the mouse pointer,
+// or -1 when it cannot tell.
+func focusedScreen(ctx context.Context) int {
+	out, err := exec.CommandContext(ctx, "osascript", "-l", "JavaScript", "-e", focusedScreenScript).Output()
+	if err != nil {
+		return -1
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return -1
+	}
+	return i
+}
+
+func decodeFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(path), err)
+	}
+	return img, nil
+}
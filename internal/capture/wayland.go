@@ -0,0 +1,149 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os/exec"
+)
+
+// waylandCapturer shells out to grim, which works on wlroots based
+// compositors. It writes a PNG to stdout so no temp file is needed. grim
+// cannot list outputs, so their layout and focus come from the compositor
+// (sway or Hyprland); elsewhere the whole layout is a single display.
+type waylandCapturer struct{}
+
+func (c *waylandCapturer) Name() string {
+	return BackendWayland
+}
+
+// waylandOutput is one monitor as reported by the compositor, in logical
+// coordinates.
+type waylandOutput struct {
+	Name    string
+	Bounds  image.Rectangle
+	Focused bool
+}
+
+func (c *waylandCapturer) Capture(ctx context.Context) ([]Display, error) {
+	if _, err := exec.LookPath("grim"); err != nil {
+		return nil, fmt.Errorf("grim not found, or use capture.backend: portal: %w", err)
+	}
+
+	outputs := waylandOutputs(ctx)
+	if len(outputs) == 0 {
+		img, err := grim(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		return []Display{{Index: 0, Bounds: img.Bounds(), Primary: true, Focused: true, Image: img}}, nil
+	}
+
+	displays := make([]Display, 0, len(outputs))
+	focused := false
+	for i, o := range outputs {
+		img, err := grim(ctx, o.Name)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", o.Name, err)
+		}
+		displays = append(displays, Display{Index: i, Bounds: o.Bounds, Primary: i == 0, Focused: o.Focused, Image: img})
+		focused = focused || o.Focused
+	}
+	if !focused {
+		markFocused(displays, image.Pt(-1, -1))
+	}
+	return displays, nil
+}
+
+// grim captures output, or every output when it is empty.
+func grim(ctx context.Context, output string) (image.Image, error) {
+	args := []string{"-t", "png"}
+	if output != "" {
+		args = append(args, "-o", output)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "grim", append(args, "-")...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("grim failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	img, _, err := image.Decode(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("decode grim output: %w", err)
+	}
+	return img, nil
+}
+
+// waylandOutputs asks sway, then Hyprland, for the active outputs. It
+// returns nil when neither answers.
+func waylandOutputs(ctx context.Context) []waylandOutput {
+	if out, err := exec.CommandContext(ctx, "swaymsg", "-t", "get_outputs", "-r").Output(); err == nil {
+		if outputs, err := parseSwayOutputs(out); err == nil {
+			return outputs
+		}
+	}
+	if out, err := exec.CommandContext(ctx, "hyprctl", "monitors", "-j").Output(); err == nil {
+		if outputs, err := parseHyprlandMonitors(out); err == nil {
+			return outputs
+		}
+	}
+	return nil
+}
+
+func parseSwayOutputs(data []byte) ([]waylandOutput, error) {
+	var raw []struct {
+		Name    string `json:"name"`
+		Active  bool   `json:"active"`
+		Focused bool   `json:"focused"`
+		Rect    struct {
+			X      int `json:"x"`
+			Y      int `json:"y"`
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"rect"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var outputs []waylandOutput
+	for _, o := range raw {
+		if !o.Active {
+			continue
+		}
+		outputs = append(outputs, waylandOutput{
+			Name:    o.Name,
+			Bounds:  image.Rect(o.Rect.X, o.Rect.Y, o.Rect.X+o.Rect.Width, o.Rect.Y+o.Rect.Height),
+			Focused: o.Focused,
+		})
+	}
+	return outputs, nil
+}
+
+func parseHyprlandMonitors(data []byte) ([]waylandOutput, error) {
+	var raw []struct {
+		Name    string  `json:"name"`
+		X       int     `json:"x"`
+		Y       int     `json:"y"`
+		Width   int     `json:"width"`
+		Height  int     `json:"height"`
+		Scale   float64 `json:"scale"`
+		Focused bool    `json:"focused"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	outputs := make([]waylandOutput, 0, len(raw))
+	for _, m := range raw {
+		// Width and height are in pixels; positions are logical.
+		w, h := m.Width, m.Height
+		if m.Scale > 0 {
+			w, h = int(float64(w)/m.Scale), int(float64(h)/m.Scale)
+		}
+		outputs = append(outputs, waylandOutput{Name: m.Name, Bounds: image.Rect(m.X, m.Y, m.X+w, m.Y+h), Focused: m.Focused})
+	}
+	return outputs, nil
+}
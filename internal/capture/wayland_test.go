@@ -0,0 +1,32 @@
+package capture
+
+import (
+	"image"
+	"testing"
+)
+
+func TestParseWaylandOutputs(t *testing.T) {
+	sway := []byte(`[
+		{"name": "eDP-1", "active": true, "focused": false, "rect": {"x": 0, "y": 0, "width": 1280, "height": 800}},
+		{"name": "HDMI-A-1", "active": false, "focused": false, "rect": {"x": 0, "y": 0, "width": 0, "height": 0}},
+		{"name": "DP-1", "active": true, "focused": true, "rect": {"x": 1280, "y": 0, "width": 1920, "height": 1080}}
+	]`)
+	outputs, err := parseSwayOutputs(sway)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 || outputs[1].Name != "DP-1" || !outputs[1].Focused || outputs[1].Bounds != image.Rect(1280, 0, 3200, 1080) {
+		t.Errorf("sway outputs = %+v", outputs)
+	}
+
+	hypr := []byte(`[
+		{"name": "eDP-1", "x": 0, "y": 0, "width": 2560, "height": 1600, "scale": 2, "focused": true},
+		{"name": "DP-1", "x": 1280, "y": 0, "width": 1920, "height": 1080, "scale": 1, "focused": false}
+	]`)
+	if outputs, err = parseHyprlandMonitors(hypr); err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 || !outputs[0].Focused || outputs[0].Bounds != image.Rect(0, 0, 1280, 800) {
+		t.Errorf("hyprland outputs = %+v", outputs)
+	}
+}
@@ -0,0 +1,109 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/xproto"
+)
+
+// x11Capturer reads the root window of the default screen over the X
+// protocol, so it needs no external tools. Monitors come from RandR 1.5; if
+// that is unavailable the whole root window is one display.
+type x11Capturer struct{}
+
+func (c *x11Capturer) Name() string {
+	return BackendX11
+}
+
+func (c *x11Capturer) Capture(ctx context.Context) ([]Display, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("connect to X server: %w", err)
+	}
+	defer conn.Close()
+
+	screen := xproto.Setup(conn).DefaultScreen(conn)
+	rootRect := image.Rect(0, 0, int(screen.WidthInPixels), int(screen.HeightInPixels))
+
+	displays := x11Monitors(conn, screen.Root)
+	if len(displays) == 0 {
+		displays = []Display{{Index: 0, Bounds: rootRect, Primary: true}}
+	}
+
+	for i := range displays {
+		bounds := displays[i].Bounds.Intersect(rootRect)
+		img, err := grabX11(conn, screen.Root, bounds)
+		if err != nil {
+			return nil, fmt.Errorf("display %d: %w", displays[i].Index, err)
+		}
+		displays[i].Image = img
+	}
+
+	pointer := image.Pt(-1, -1)
+	if reply, err := xproto.QueryPointer(conn, screen.Root).Reply(); err == nil {
+		pointer = image.Pt(int(reply.RootX), int(reply.RootY))
+	}
+	markFocused(displays, pointer)
+	return displays, nil
+}
+
+func x11Monitors(conn *xgb.Conn, root xproto.Window) []Display {
+	if err := randr.Init(conn); err != nil {
+		return nil
+	}
+	reply, err := randr.GetMonitors(conn, root, true).Reply()
+	if err != nil {
+		return nil
+	}
+
+	displays := make([]Display, 0, len(reply.Monitors))
+	for _, m := range reply.Monitors {
+		displays = append(displays, Display{
+			Bounds:  image.Rect(int(m.X), int(m.Y), int(m.X)+int(m.Width), int(m.Y)+int(m.Height)),
+			Primary: m.Primary,
+		})
+	}
+	// Number monitors left to right, top to bottom so indexes are stable
+	// across captures regardless of the order RandR reports them.
+	sort.SliceStable(displays, func(i, j int) bool {
+		a, b := displays[i].Bounds.Min, displays[j].Bounds.Min
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.Y < b.Y
+	})
+	for i := range displays {
+		displays[i].Index = i
+	}
+	return displays
+}
+
+// grabX11 fetches rect of the drawable as a ZPixmap. The server sends 32 bits
+// per pixel in BGRX order for 24 and 32 bit visuals.
+func grabX11(conn *xgb.Conn, drawable xproto.Window, rect image.Rectangle) (*image.RGBA, error) {
+	reply, err := xproto.GetImage(conn, xproto.ImageFormatZPixmap, xproto.Drawable(drawable),
+		int16(rect.Min.X), int16(rect.Min.Y), uint16(rect.Dx()), uint16(rect.Dy()), 0xffffffff).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("get image: %w", err)
+	}
+	if reply.Depth != 24 && reply.Depth != 32 {
+		return nil, fmt.Errorf("unsupported X11 depth: %d", reply.Depth)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	if len(reply.Data) < len(img.Pix) {
+		return nil, fmt.Errorf("short X11 image: got %d bytes, want %d", len(reply.Data), len(img.Pix))
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i] = reply.Data[i+2]
+		img.Pix[i+1] = reply.Data[i+1]
+		img.Pix[i+2] = reply.Data[i]
+		img.Pix[i+3] = 0xff
+	}
+	return img, nil
+}
@@ -0,0 +1,55 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+const (
+	ProviderCopilot = "copilot"
+	ProviderOpenAI  = "openai"
+	ProviderOllama  = "ollama"
+)
+
+type Result struct {
+	SelectedCategoryID string   `json:"selectedCategoryId"`
+	Confidence         float64  `json:"confidence"`
+	Rationale          string   `json:"rationale"`
+	DetectedApps       []string `json:"detectedApps,omitempty"`
+	DetectedKeywords   []string `json:"detectedKeywords,omitempty"`
+	// Source records whether a rule or the model produced the result.
+	Source string `json:"-"`
+}
+
+// Screenshot is one image attached to a classification request. Description
+// tells the model what the image shows, e.g. which display it came from.
+type Screenshot struct {
+	Path        string
+	Description string
+}
+
+// Classifier picks one of the configured categories for a set of
+// screenshots.
+type Classifier interface {
+	// Name is the provider name, e.g. "copilot".
+	Name() string
+	// Model is the model the provider talks to.
+	Model() string
+	Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error)
+}
+
+// New builds the Classifier selected by cfg.Provider.
+func New(cfg config.ClassifierConfig) (Classifier, error) {
+	switch cfg.Provider {
+	case ProviderCopilot:
+		return NewCopilot(cfg.Copilot), nil
+	case ProviderOpenAI:
+		return NewOpenAI(cfg.OpenAI), nil
+	case ProviderOllama:
+		return NewOllama(cfg.Ollama), nil
+	default:
+		return nil, fmt.Errorf("unknown classifier provider: %s", cfg.Provider)
+	}
+}
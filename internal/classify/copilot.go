@@ -0,0 +1,218 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+const (
+	defaultSessionMaxUses = 20
+	defaultSessionMaxAge  = time.Hour
+)
+
+// copilotClient and copilotSession are the parts of the SDK we use, so
+// tests can substitute a fake CLI.
+type copilotClient interface {
+	Start() error
+	Stop() []error
+	GetState() copilot.ConnectionState
+	Ping(message string) (*copilot.PingResponse, error)
+	CreateSession(config *copilot.SessionConfig) (copilotSession, error)
+}
+
+type copilotSession interface {
+	SendAndWait(options copilot.MessageOptions, timeout time.Duration) (*copilot.SessionEvent, error)
+	Destroy() error
+}
+
+type sdkClient struct {
+	*copilot.Client
+}
+
+func (c sdkClient) CreateSession(config *copilot.SessionConfig) (copilotSession, error) {
+	return c.Client.CreateSession(config)
+}
+
+// Copilot classifies through the GitHub Copilot SDK, which drives the
+// locally installed copilot CLI. The CLI process and session are kept alive
+// between calls; the session is replaced after maxUses classifications or
+// maxAge so its context does not grow without bound, and everything is
+// restarted when the CLI stops answering.
+type Copilot struct {
+	model   string
+	maxUses int
+	maxAge  time.Duration
+
+	newClient func() copilotClient
+	now       func() time.Time
+
+	mu             sync.Mutex
+	client         copilotClient
+	session        copilotSession
+	sessionStarted time.Time
+	sessionUses    int
+}
+
+func NewCopilot(cfg config.CopilotConfig) *Copilot {
+	c := &Copilot{
+		model:     cfg.Model,
+		maxUses:   cfg.SessionMaxUses,
+		maxAge:    time.Duration(cfg.SessionMaxAgeMinutes) * time.Minute,
+		newClient: func() copilotClient { return sdkClient{copilot.NewClient(nil)} },
+		now:       time.Now,
+	}
+	if c.maxUses <= 0 {
+		c.maxUses = defaultSessionMaxUses
+	}
+	if c.maxAge <= 0 {
+		c.maxAge = defaultSessionMaxAge
+	}
+	return c
+}
+
+func (c *Copilot) Name() string {
+	return ProviderCopilot
+}
+
+func (c *Copilot) Model() string {
+	return c.model
+}
+
+func (c *Copilot) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	if err := checkScreenshots(screenshots); err != nil {
+		return nil, err
+	}
+
+	prompt, err := buildPrompt(screenshots, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session, err := c.ensureSession()
+	if err != nil {
+		return nil, err
+	}
+	c.sessionUses++
+
+	conv := &copilotConversation{session: session, attachments: make([]copilot.Attachment, 0, len(screenshots))}
+	for _, s := range screenshots {
+		conv.attachments = append(conv.attachments, copilot.Attachment{
+			DisplayName: filepath.Base(s.Path),
+			Path:        s.Path,
+			Type:        copilot.File,
+		})
+	}
+
+	result, err := ask(ctx, conv, prompt, categories)
+	if err != nil && KindOf(err) != ErrBadResponse {
+		// The session or CLI may be wedged; start from scratch next time.
+		c.reset()
+	}
+	return result, err
+}
+
+// Close stops the CLI. The next Classify call starts it again.
+func (c *Copilot) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reset()
+	return nil
+}
+
+// ensureSession returns a healthy session, starting the CLI, reconnecting or
+// rotating the session as needed. c.mu must be held.
+func (c *Copilot) ensureSession() (copilotSession, error) {
+	if c.client != nil && !c.healthy() {
+		log.Println("copilot client unhealthy, reconnecting")
+		c.reset()
+	}
+
+	if c.client == nil {
+		client := c.newClient()
+		if err := client.Start(); err != nil {
+			return nil, err
+		}
+		c.client = client
+	}
+
+	if c.session != nil && (c.sessionUses >= c.maxUses || c.now().Sub(c.sessionStarted) >= c.maxAge) {
+		if err := c.session.Destroy(); err != nil {
+			log.Printf("Warning: failed to destroy copilot session: %v", err)
+		}
+		c.session = nil
+	}
+
+	if c.session == nil {
+		session, err := c.client.CreateSession(&copilot.SessionConfig{Model: c.model})
+		if err != nil {
+			c.reset()
+			return nil, err
+		}
+		c.session = session
+		c.sessionStarted = c.now()
+		c.sessionUses = 0
+	}
+	return c.session, nil
+}
+
+func (c *Copilot) healthy() bool {
+	if c.client.GetState() != copilot.StateConnected {
+		return false
+	}
+	_, err := c.client.Ping("")
+	return err == nil
+}
+
+// reset tears down the session and CLI. c.mu must be held.
+func (c *Copilot) reset() {
+	if c.session != nil {
+		_ = c.session.Destroy()
+		c.session = nil
+	}
+	if c.client != nil {
+		for _, err := range c.client.Stop() {
+			log.Printf("Warning: copilot stop: %v", err)
+		}
+		c.client = nil
+	}
+}
+
+type copilotConversation struct {
+	session     copilotSession
+	attachments []copilot.Attachment
+}
+
+func (c *copilotConversation) send(ctx context.Context, text string) (string, error) {
+	opts := copilot.MessageOptions{Prompt: text, Attachments: c.attachments}
+	// The session keeps the images, so only the first message attaches them.
+	c.attachments = nil
+
+	// SendAndWait has no context parameter, so map the deadline onto its
+	// timeout. Zero means the SDK default.
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			return "", newError(ErrTimeout, ctx.Err())
+		}
+	}
+
+	resp, err := c.session.SendAndWait(opts, timeout)
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.Data.Content == nil {
+		return "", newError(ErrBadResponse, fmt.Errorf("empty response"))
+	}
+	return *resp.Data.Content, nil
+}
@@ -0,0 +1,105 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+type fakeCopilotCLI struct {
+	starts, sessions, destroyed, stops int
+	pingErr                            error
+	sendErr                            error
+}
+
+type fakeCopilotClient struct{ cli *fakeCopilotCLI }
+
+func (c fakeCopilotClient) Start() error                      { c.cli.starts++; return nil }
+func (c fakeCopilotClient) Stop() []error                     { c.cli.stops++; return nil }
+func (c fakeCopilotClient) GetState() copilot.ConnectionState { return copilot.StateConnected }
+func (c fakeCopilotClient) Ping(string) (*copilot.PingResponse, error) {
+	return &copilot.PingResponse{}, c.cli.pingErr
+}
+func (c fakeCopilotClient) CreateSession(*copilot.SessionConfig) (copilotSession, error) {
+	c.cli.sessions++
+	return fakeCopilotSession{c.cli}, nil
+}
+
+type fakeCopilotSession struct{ cli *fakeCopilotCLI }
+
+func (s fakeCopilotSession) SendAndWait(copilot.MessageOptions, time.Duration) (*copilot.SessionEvent, error) {
+	if s.cli.sendErr != nil {
+		return nil, s.cli.sendErr
+	}
+	content := `{"selectedCategoryId":"meeting","confidence":0.9}`
+	return &copilot.SessionEvent{Data: copilot.Data{Content: &content}}, nil
+}
+func (s fakeCopilotSession) Destroy() error { s.cli.destroyed++; return nil }
+
+func newFakeCopilot(cli *fakeCopilotCLI, maxUses int) (*Copilot, *time.Time) {
+	now := time.Date(2026, 1, 28, 9, 0, 0, 0, time.UTC)
+	c := &Copilot{
+		model:     "gpt-4.1",
+		maxUses:   maxUses,
+		maxAge:    time.Hour,
+		newClient: func() copilotClient { return fakeCopilotClient{cli} },
+		now:       func() time.Time { return now },
+	}
+	return c, &now
+}
+
+func TestCopilotReusesAndRotatesSession(t *testing.T) {
+	cli := &fakeCopilotCLI{}
+	c, now := newFakeCopilot(cli, 2)
+	shots := testScreenshot(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Classify(context.Background(), shots, testCategories); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cli.starts != 1 || cli.sessions != 2 || cli.destroyed != 1 {
+		t.Errorf("after 3 calls with maxUses=2: starts=%d sessions=%d destroyed=%d", cli.starts, cli.sessions, cli.destroyed)
+	}
+
+	*now = now.Add(2 * time.Hour)
+	if _, err := c.Classify(context.Background(), shots, testCategories); err != nil {
+		t.Fatal(err)
+	}
+	if cli.sessions != 3 {
+		t.Errorf("session older than maxAge should rotate, sessions=%d", cli.sessions)
+	}
+}
+
+func TestCopilotReconnects(t *testing.T) {
+	cli := &fakeCopilotCLI{}
+	c, _ := newFakeCopilot(cli, 20)
+	shots := testScreenshot(t)
+
+	if _, err := c.Classify(context.Background(), shots, testCategories); err != nil {
+		t.Fatal(err)
+	}
+
+	cli.pingErr = errors.New("broken pipe")
+	if _, err := c.Classify(context.Background(), shots, testCategories); err != nil {
+		t.Fatal(err)
+	}
+	if cli.starts != 2 || cli.stops != 1 {
+		t.Errorf("failed health check should restart the CLI: starts=%d stops=%d", cli.starts, cli.stops)
+	}
+
+	cli.pingErr = nil
+	cli.sendErr = errors.New("connection reset")
+	if _, err := c.Classify(context.Background(), shots, testCategories); err == nil {
+		t.Fatal("expected send error")
+	}
+	if c.client != nil {
+		t.Error("client should be torn down after a transport error")
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
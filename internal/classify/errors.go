@@ -0,0 +1,89 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrorKind says why a classification failed. It is stored on failed events.
+type ErrorKind string
+
+const (
+	ErrTimeout     ErrorKind = "timeout"
+	ErrAuth        ErrorKind = "auth"
+	ErrRateLimit   ErrorKind = "rate_limit"
+	ErrBadResponse ErrorKind = "bad_response"
+	ErrUnavailable ErrorKind = "unavailable"
+)
+
+// Error wraps a provider error with its kind.
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind ErrorKind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+// withKind tags err with kind unless a provider already tagged it, so the
+// kind is not repeated in the message.
+func withKind(kind ErrorKind, err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return newError(kind, err)
+}
+
+// KindOf classifies err. Errors that were not tagged by a provider are
+// sorted by looking at the context error and, for SDK errors we cannot
+// inspect, the message text.
+func KindOf(err error) ErrorKind {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out"):
+		return ErrTimeout
+	case strings.Contains(msg, "unauthorized") || strings.Contains(msg, "forbidden") || strings.Contains(msg, "authenticat"):
+		return ErrAuth
+	case strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests"):
+		return ErrRateLimit
+	}
+	return ErrUnavailable
+}
+
+// retryable reports whether another attempt could succeed. Auth problems
+// and bad responses (which were already re-prompted) are final.
+func retryable(kind ErrorKind) bool {
+	switch kind {
+	case ErrTimeout, ErrRateLimit, ErrUnavailable:
+		return true
+	}
+	return false
+}
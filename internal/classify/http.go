@@ -0,0 +1,61 @@
+package classify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// postJSON sends body as JSON and decodes a JSON response into out.
+// Non-2xx responses are returned as errors including the response body,
+// tagged with an ErrorKind derived from the status code.
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newError(statusKind(resp.StatusCode), fmt.Errorf("%s: %s: %s", url, resp.Status, bytes.TrimSpace(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return newError(ErrBadResponse, fmt.Errorf("decode response: %w", err))
+	}
+	return nil
+}
+
+func statusKind(status int) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrAuth
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimit
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrTimeout
+	case status >= 500:
+		return ErrUnavailable
+	}
+	return ErrBadResponse
+}
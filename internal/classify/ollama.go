@@ -0,0 +1,108 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+const defaultOllamaURL = "http://localhost:11434"
+
+// Ollama uses the native /api/chat endpoint of a local Ollama server with a
+// vision capable model such as llava.
+type Ollama struct {
+	baseURL string
+	model   string
+	http    *http.Client
+}
+
+func NewOllama(cfg config.OllamaConfig) *Ollama {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	return &Ollama{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   cfg.Model,
+		http:    &http.Client{},
+	}
+}
+
+func (c *Ollama) Name() string {
+	return ProviderOllama
+}
+
+func (c *Ollama) Model() string {
+	return c.model
+}
+
+type ollamaMessage struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"`
+}
+
+type ollamaRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+	Format   string          `json:"format,omitempty"`
+}
+
+type ollamaResponse struct {
+	Message ollamaMessage `json:"message"`
+}
+
+func (c *Ollama) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	if err := checkScreenshots(screenshots); err != nil {
+		return nil, err
+	}
+
+	prompt, err := buildPrompt(screenshots, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]string, 0, len(screenshots))
+	for _, s := range screenshots {
+		data, _, err := readImageBase64(s.Path)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, data)
+	}
+
+	conv := &ollamaConversation{client: c, images: images}
+	return ask(ctx, conv, prompt, categories)
+}
+
+type ollamaConversation struct {
+	client   *Ollama
+	messages []ollamaMessage
+	// images are attached to the first message only.
+	images []string
+}
+
+func (conv *ollamaConversation) send(ctx context.Context, text string) (string, error) {
+	conv.messages = append(conv.messages, ollamaMessage{Role: "user", Content: text, Images: conv.images})
+	conv.images = nil
+
+	var resp ollamaResponse
+	req := ollamaRequest{
+		Model:    conv.client.model,
+		Messages: conv.messages,
+		Format:   "json",
+	}
+	if err := postJSON(ctx, conv.client.http, conv.client.baseURL+"/api/chat", nil, req, &resp); err != nil {
+		return "", err
+	}
+	if resp.Message.Content == "" {
+		return "", newError(ErrBadResponse, fmt.Errorf("empty response"))
+	}
+
+	conv.messages = append(conv.messages, ollamaMessage{Role: "assistant", Content: resp.Message.Content})
+	return resp.Message.Content, nil
+}
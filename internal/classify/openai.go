@@ -0,0 +1,128 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+// OpenAI talks to any server implementing the OpenAI chat completions API
+// with image input, such as llama.cpp's server or vLLM.
+type OpenAI struct {
+	baseURL string
+	model   string
+	apiKey  string
+	http    *http.Client
+}
+
+func NewOpenAI(cfg config.OpenAIConfig) *OpenAI {
+	apiKey := ""
+	if cfg.APIKeyEnv != "" {
+		apiKey = os.Getenv(cfg.APIKeyEnv)
+	}
+	return &OpenAI{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		model:   cfg.Model,
+		apiKey:  apiKey,
+		http:    &http.Client{},
+	}
+}
+
+func (c *OpenAI) Name() string {
+	return ProviderOpenAI
+}
+
+func (c *OpenAI) Model() string {
+	return c.model
+}
+
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIMessage struct {
+	Role    string              `json:"role"`
+	Content []openAIContentPart `json:"content"`
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+func (c *OpenAI) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	if err := checkScreenshots(screenshots); err != nil {
+		return nil, err
+	}
+
+	prompt, err := buildPrompt(screenshots, categories)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []openAIContentPart
+	for _, s := range screenshots {
+		data, mimeType, err := readImageBase64(s.Path)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, openAIContentPart{
+			Type:     "image_url",
+			ImageURL: &openAIImageURL{URL: "data:" + mimeType + ";base64," + data},
+		})
+	}
+
+	conv := &openAIConversation{client: c, images: images}
+	return ask(ctx, conv, prompt, categories)
+}
+
+// openAIConversation replays the message history on every request since the
+// API is stateless.
+type openAIConversation struct {
+	client   *OpenAI
+	messages []openAIMessage
+	// images are attached to the first message only.
+	images []openAIContentPart
+}
+
+func (conv *openAIConversation) send(ctx context.Context, text string) (string, error) {
+	parts := append([]openAIContentPart{{Type: "text", Text: text}}, conv.images...)
+	conv.images = nil
+	conv.messages = append(conv.messages, openAIMessage{Role: "user", Content: parts})
+
+	header := http.Header{}
+	if conv.client.apiKey != "" {
+		header.Set("Authorization", "Bearer "+conv.client.apiKey)
+	}
+
+	var resp openAIResponse
+	req := openAIRequest{Model: conv.client.model, Messages: conv.messages}
+	if err := postJSON(ctx, conv.client.http, conv.client.baseURL+"/chat/completions", header, req, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+		return "", newError(ErrBadResponse, fmt.Errorf("empty response"))
+	}
+
+	content := resp.Choices[0].Message.Content
+	conv.messages = append(conv.messages, openAIMessage{Role: "assistant", Content: []openAIContentPart{{Type: "text", Text: content}}})
+	return content, nil
+}
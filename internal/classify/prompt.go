@@ -0,0 +1,69 @@
+package classify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+func checkScreenshots(screenshots []Screenshot) error {
+	if len(screenshots) == 0 {
+		return fmt.Errorf("no screenshots to classify")
+	}
+	for _, s := range screenshots {
+		if s.Path == "" {
+			return fmt.Errorf("image path is empty")
+		}
+		if _, err := os.Stat(s.Path); err != nil {
+			return fmt.Errorf("image path is not accessible: %w", err)
+		}
+	}
+	return nil
+}
+
+func buildPrompt(screenshots []Screenshot, categories []config.CategoryConfig) (string, error) {
+	catsJSON, err := json.Marshal(categories)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(`You are a screenshot classifier.
+Use the attached images to classify what the user is doing.
+When several displays are shown, weigh the focused display most heavily.
+Return ONLY valid JSON with keys: selectedCategoryId, confidence, rationale, detectedApps, detectedKeywords.
+Choose exactly one category id from the list.
+Categories: %s
+Attachments:
+%s`, string(catsJSON), describeScreenshots(screenshots)), nil
+}
+
+func describeScreenshots(screenshots []Screenshot) string {
+	var sb strings.Builder
+	for _, s := range screenshots {
+		sb.WriteString("- " + filepath.Base(s.Path))
+		if s.Description != "" {
+			sb.WriteString(": " + s.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// readImageBase64 loads a screenshot for providers that take inline images
+// rather than file attachments.
+func readImageBase64(path string) (data string, mimeType string, err error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	mimeType = "image/jpeg"
+	if strings.EqualFold(filepath.Ext(path), ".png") {
+		mimeType = "image/png"
+	}
+	return base64.StdEncoding.EncodeToString(raw), mimeType, nil
+}
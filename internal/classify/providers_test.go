@@ -0,0 +1,100 @@
+package classify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+func testScreenshot(t *testing.T) []Screenshot {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(path, []byte("not really a png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return []Screenshot{{Path: path, Description: "the only display"}}
+}
+
+var testCategories = []config.CategoryConfig{{ID: "implement", Name: "実装"}, {ID: "meeting", Name: "会議"}}
+
+func TestOpenAIClassify(t *testing.T) {
+	t.Setenv("TEST_OPENAI_KEY", "secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("authorization = %q", got)
+		}
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Model != "llava" || len(req.Messages[0].Content) != 2 ||
+			!strings.HasPrefix(req.Messages[0].Content[1].ImageURL.URL, "data:image/png;base64,") {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"{\"selectedCategoryId\":\"meeting\",\"confidence\":0.8}"}}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(config.ClassifierConfig{
+		Provider: ProviderOpenAI,
+		OpenAI:   config.OpenAIConfig{BaseURL: srv.URL + "/v1/", Model: "llava", APIKeyEnv: "TEST_OPENAI_KEY"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := c.Classify(context.Background(), testScreenshot(t), testCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelectedCategoryID != "meeting" || res.Confidence != 0.8 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestOllamaClassify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Stream || req.Format != "json" || len(req.Messages[0].Images) != 1 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"{\"selectedCategoryId\":\"implement\",\"confidence\":0.9}"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewOllama(config.OllamaConfig{BaseURL: srv.URL, Model: "llava"})
+	res, err := c.Classify(context.Background(), testScreenshot(t), testCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelectedCategoryID != "implement" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
+
+func TestHTTPErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := NewOllama(config.OllamaConfig{BaseURL: srv.URL, Model: "missing"})
+	if _, err := c.Classify(context.Background(), testScreenshot(t), testCategories); err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("expected error with response body, got %v", err)
+	}
+}
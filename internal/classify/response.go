@@ -0,0 +1,152 @@
+package classify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// conversation is one exchange with a model. The first message carries the
+// screenshots; later messages are plain text follow-ups in the same context.
+type conversation interface {
+	send(ctx context.Context, text string) (string, error)
+}
+
+// ask sends prompt and decodes the reply. If the reply cannot be parsed or
+// fails validation, the model is told what was wrong and asked once more.
+func ask(ctx context.Context, conv conversation, prompt string, categories []config.CategoryConfig) (*Result, error) {
+	reply, err := conv.send(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	result, err := decodeResult(reply, categories)
+	if err == nil {
+		return result, nil
+	}
+
+	reply, err = conv.send(ctx, correctionPrompt(err))
+	if err != nil {
+		return nil, err
+	}
+	result, err = decodeResult(reply, categories)
+	if err != nil {
+		return nil, newError(ErrBadResponse, fmt.Errorf("invalid response after retry: %w", err))
+	}
+	return result, nil
+}
+
+func correctionPrompt(err error) string {
+	return fmt.Sprintf(`Your previous reply was rejected: %v
+Reply again with ONLY a single JSON object with keys: selectedCategoryId, confidence, rationale, detectedApps, detectedKeywords.
+selectedCategoryId must be one of the listed category ids and confidence must be between 0 and 1.`, err)
+}
+
+// decodeResult extracts the JSON object from a model reply, validates it
+// against the result schema for categories and unmarshals it.
+func decodeResult(content string, categories []config.CategoryConfig) (*Result, error) {
+	raw, err := extractJSON(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var instance map[string]any
+	if err := json.Unmarshal([]byte(raw), &instance); err != nil {
+		return nil, fmt.Errorf("invalid json response: %w", err)
+	}
+
+	schema, err := resultSchema(categories).Resolve(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := schema.Validate(instance); err != nil {
+		return nil, fmt.Errorf("response does not match schema: %w", err)
+	}
+
+	var result Result
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, fmt.Errorf("invalid json response: %w", err)
+	}
+	return &result, nil
+}
+
+func resultSchema(categories []config.CategoryConfig) *jsonschema.Schema {
+	ids := make([]any, 0, len(categories))
+	for _, c := range categories {
+		ids = append(ids, c.ID)
+	}
+	stringList := func() *jsonschema.Schema {
+		return &jsonschema.Schema{Types: []string{"array", "null"}, Items: &jsonschema.Schema{Type: "string"}}
+	}
+
+	return &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"selectedCategoryId", "confidence"},
+		Properties: map[string]*jsonschema.Schema{
+			"selectedCategoryId": {Type: "string", Enum: ids},
+			"confidence":         {Type: "number", Minimum: jsonschema.Ptr(0.0), Maximum: jsonschema.Ptr(1.0)},
+			"rationale":          {Type: "string"},
+			"detectedApps":       stringList(),
+			"detectedKeywords":   stringList(),
+		},
+	}
+}
+
+// extractJSON returns the first complete JSON object in s. Models often wrap
+// the object in markdown fences or add prose before or after it.
+func extractJSON(s string) (string, error) {
+	start := strings.IndexByte(s, '{')
+	for start >= 0 {
+		if end := matchBrace(s[start:]); end > 0 {
+			candidate := s[start : start+end]
+			if json.Valid([]byte(candidate)) {
+				return candidate, nil
+			}
+		}
+		next := strings.IndexByte(s[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return "", fmt.Errorf("no JSON object found in response: %q", truncate(s, 200))
+}
+
+// matchBrace returns the length of the brace-balanced prefix of s, which
+// must start with '{', or -1 if the braces never balance. Braces inside
+// strings are ignored.
+func matchBrace(s string) int {
+	depth := 0
+	inString := false
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,77 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"a":1}`, `{"a":1}`},
+		{"```json\n{\"a\":1}\n```", `{"a":1}`},
+		{"Here you go:\n{\"a\":\"}{\"} \nHope this helps!", `{"a":"}{"}`},
+		{`{not json} then {"a":{"b":2}}`, `{"a":{"b":2}}`},
+	}
+	for _, tt := range tests {
+		got, err := extractJSON(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("extractJSON(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := extractJSON("no object here"); err == nil {
+		t.Error("expected error for text without JSON")
+	}
+}
+
+func TestDecodeResultValidation(t *testing.T) {
+	if _, err := decodeResult(`{"selectedCategoryId":"meeting","confidence":0.7}`, testCategories); err != nil {
+		t.Errorf("valid response rejected: %v", err)
+	}
+	for _, bad := range []string{
+		`{"selectedCategoryId":"gaming","confidence":0.7}`,
+		`{"selectedCategoryId":"meeting","confidence":1.5}`,
+		`{"confidence":0.7}`,
+	} {
+		if _, err := decodeResult(bad, testCategories); err == nil {
+			t.Errorf("decodeResult(%s) should fail", bad)
+		}
+	}
+}
+
+type scriptedConversation struct {
+	replies []string
+	sent    []string
+}
+
+func (c *scriptedConversation) send(ctx context.Context, text string) (string, error) {
+	c.sent = append(c.sent, text)
+	if len(c.replies) == 0 {
+		return "", fmt.Errorf("no more replies")
+	}
+	reply := c.replies[0]
+	c.replies = c.replies[1:]
+	return reply, nil
+}
+
+func TestAskRepromptsOnce(t *testing.T) {
+	conv := &scriptedConversation{replies: []string{
+		`{"selectedCategoryId":"gaming","confidence":0.7}`,
+		"```json\n{\"selectedCategoryId\":\"meeting\",\"confidence\":0.7}\n```",
+	}}
+	res, err := ask(context.Background(), conv, "classify", testCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelectedCategoryID != "meeting" || len(conv.sent) != 2 || !strings.Contains(conv.sent[1], "rejected") {
+		t.Errorf("unexpected retry flow: %+v, sent=%q", res, conv.sent)
+	}
+
+	conv = &scriptedConversation{replies: []string{"nope", "still nope", "never asked"}}
+	if _, err := ask(context.Background(), conv, "classify", testCategories); err == nil || len(conv.sent) != 2 {
+		t.Errorf("expected failure after one retry, got err=%v sent=%d", err, len(conv.sent))
+	}
+}
@@ -0,0 +1,113 @@
+package classify
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+const (
+	defaultTimeout    = 2 * time.Minute
+	defaultBackoff    = 2 * time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// Retrying bounds each classification attempt with a timeout and retries
+// transient failures with exponential backoff and full jitter.
+type Retrying struct {
+	Classifier Classifier
+	Timeout    time.Duration
+	MaxRetries int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// sleep and jitter are replaced in tests.
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(max time.Duration) time.Duration
+}
+
+func NewRetrying(c Classifier, cfg config.ClassifierConfig) *Retrying {
+	r := &Retrying{
+		Classifier: c,
+		Timeout:    time.Duration(cfg.TimeoutSeconds) * time.Second,
+		MaxRetries: cfg.MaxRetries,
+		Backoff:    time.Duration(cfg.BackoffSeconds) * time.Second,
+		MaxBackoff: defaultMaxBackoff,
+		sleep:      sleepContext,
+		jitter:     func(max time.Duration) time.Duration { return time.Duration(rand.Int63n(int64(max) + 1)) },
+	}
+	if r.Timeout <= 0 {
+		r.Timeout = defaultTimeout
+	}
+	if r.Backoff <= 0 {
+		r.Backoff = defaultBackoff
+	}
+	return r
+}
+
+func (r *Retrying) Name() string {
+	return r.Classifier.Name()
+}
+
+func (r *Retrying) Model() string {
+	return r.Classifier.Model()
+}
+
+// Close releases the wrapped classifier's resources, if it holds any.
+func (r *Retrying) Close() error {
+	if c, ok := r.Classifier.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (r *Retrying) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		result, err := r.attempt(ctx, screenshots, categories)
+		if err == nil {
+			return result, nil
+		}
+		kind := KindOf(err)
+		lastErr = withKind(kind, err)
+		if !retryable(kind) || attempt >= r.MaxRetries || ctx.Err() != nil {
+			return nil, lastErr
+		}
+
+		wait := r.jitter(r.backoff(attempt))
+		log.Printf("classification attempt %d failed (%s), retrying in %v: %v", attempt+1, kind, wait, err)
+		if err := r.sleep(ctx, wait); err != nil {
+			return nil, lastErr
+		}
+	}
+}
+
+func (r *Retrying) attempt(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+	return r.Classifier.Classify(ctx, screenshots, categories)
+}
+
+// backoff is the upper bound of the wait before retry number attempt+1.
+func (r *Retrying) backoff(attempt int) time.Duration {
+	d := r.Backoff << attempt
+	if d <= 0 || d > r.MaxBackoff {
+		return r.MaxBackoff
+	}
+	return d
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,99 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+type failingClassifier struct {
+	errs  []error
+	calls int
+}
+
+func (f *failingClassifier) Name() string  { return "failing" }
+func (f *failingClassifier) Model() string { return "m" }
+func (f *failingClassifier) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	f.calls++
+	if len(f.errs) == 0 {
+		return &Result{SelectedCategoryID: "meeting"}, nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return nil, err
+}
+
+func newTestRetrying(c Classifier, maxRetries int) (*Retrying, *[]time.Duration) {
+	var waits []time.Duration
+	r := NewRetrying(c, config.ClassifierConfig{MaxRetries: maxRetries, BackoffSeconds: 1})
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	r.jitter = func(max time.Duration) time.Duration { return max }
+	return r, &waits
+}
+
+func TestRetryingBackoff(t *testing.T) {
+	inner := &failingClassifier{errs: []error{
+		newError(ErrRateLimit, errors.New("429")),
+		context.DeadlineExceeded,
+	}}
+	r, waits := newTestRetrying(inner, 3)
+
+	res, err := r.Classify(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelectedCategoryID != "meeting" || inner.calls != 3 {
+		t.Errorf("got %+v after %d calls", res, inner.calls)
+	}
+	if len(*waits) != 2 || (*waits)[0] != time.Second || (*waits)[1] != 2*time.Second {
+		t.Errorf("waits = %v, want [1s 2s]", *waits)
+	}
+}
+
+func TestRetryingStopsOnFinalErrors(t *testing.T) {
+	inner := &failingClassifier{errs: []error{newError(ErrAuth, errors.New("401"))}}
+	r, _ := newTestRetrying(inner, 3)
+	_, err := r.Classify(context.Background(), nil, nil)
+	if KindOf(err) != ErrAuth || inner.calls != 1 {
+		t.Errorf("auth error should not be retried: kind=%s calls=%d", KindOf(err), inner.calls)
+	}
+	if got := err.Error(); got != "auth: 401" {
+		t.Errorf("error = %q, want the provider's error once", got)
+	}
+
+	inner = &failingClassifier{errs: []error{errors.New("boom"), errors.New("boom"), errors.New("boom")}}
+	r, _ = newTestRetrying(inner, 1)
+	_, err = r.Classify(context.Background(), nil, nil)
+	if KindOf(err) != ErrUnavailable || inner.calls != 2 {
+		t.Errorf("expected give up after 1 retry: kind=%s calls=%d", KindOf(err), inner.calls)
+	}
+	if got := err.Error(); got != "unavailable: boom" {
+		t.Errorf("error = %q", got)
+	}
+}
+
+func TestRetryingTimeout(t *testing.T) {
+	slow := classifierFunc(func(ctx context.Context) (*Result, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	r, _ := newTestRetrying(slow, 0)
+	r.Timeout = 10 * time.Millisecond
+	if _, err := r.Classify(context.Background(), nil, nil); KindOf(err) != ErrTimeout {
+		t.Errorf("kind = %s, want timeout", KindOf(err))
+	}
+}
+
+type classifierFunc func(ctx context.Context) (*Result, error)
+
+func (f classifierFunc) Name() string  { return "func" }
+func (f classifierFunc) Model() string { return "m" }
+func (f classifierFunc) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	return f(ctx)
+}
@@ -0,0 +1,117 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/aknow2/beholder/internal/activewin"
+	"github.com/aknow2/beholder/internal/config"
+)
+
+// Values for Result.Source.
+const (
+	SourceRule = "rule"
+	SourceLLM  = "llm"
+)
+
+// Rules classifies from the focused window using the per-category rules in
+// the config and only calls Fallback when no rule matches or the focused
+// window cannot be determined.
+type Rules struct {
+	Window   activewin.Source
+	Fallback Classifier
+	rules    []rule
+}
+
+// rule is one compiled pattern of a category, tried against the window's
+// kind of value: app, title or url.
+type rule struct {
+	categoryID string
+	kind       string
+	pattern    *regexp.Regexp
+}
+
+// NewRules compiles the rules of categories once, in config order. The
+// categories later passed to Classify are only handed to the fallback, so
+// build a new Rules when they change.
+func NewRules(window activewin.Source, fallback Classifier, categories []config.CategoryConfig) (*Rules, error) {
+	r := &Rules{Window: window, Fallback: fallback}
+	for _, c := range categories {
+		for _, kind := range []struct {
+			name     string
+			patterns []string
+		}{
+			{"app", c.Rules.Apps},
+			{"title", c.Rules.Titles},
+			{"url", c.Rules.URLs},
+		} {
+			for _, p := range kind.patterns {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					return nil, fmt.Errorf("category %s: invalid rule pattern %q: %w", c.ID, p, err)
+				}
+				r.rules = append(r.rules, rule{categoryID: c.ID, kind: kind.name, pattern: re})
+			}
+		}
+	}
+	return r, nil
+}
+
+func (r *Rules) Name() string {
+	return r.Fallback.Name()
+}
+
+func (r *Rules) Model() string {
+	return r.Fallback.Model()
+}
+
+func (r *Rules) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	if len(r.rules) > 0 {
+		info, err := r.Window.Active(ctx)
+		if err != nil {
+			log.Printf("active window lookup failed, using %s: %v", r.Fallback.Name(), err)
+		} else if result := r.Match(info); result != nil {
+			return result, nil
+		}
+	}
+
+	result, err := r.Fallback.Classify(ctx, screenshots, categories)
+	if err != nil {
+		return nil, err
+	}
+	result.Source = SourceLLM
+	return result, nil
+}
+
+// Match returns a result for the first category whose rules match info, or
+// nil if none do. Categories are tried in config order.
+func (r *Rules) Match(info *activewin.Info) *Result {
+	if info == nil {
+		return nil
+	}
+	url := info.URL
+	if url == "" {
+		url = info.Title
+	}
+	values := map[string]string{"app": info.Process, "title": info.Title, "url": url}
+
+	for _, rl := range r.rules {
+		value := values[rl.kind]
+		if value == "" || !rl.pattern.MatchString(value) {
+			continue
+		}
+		result := &Result{
+			SelectedCategoryID: rl.categoryID,
+			Confidence:         1,
+			Rationale:          fmt.Sprintf("%s %q matched rule %q", rl.kind, value, rl.pattern),
+			Source:             SourceRule,
+		}
+		if info.Process != "" {
+			result.DetectedApps = []string{info.Process}
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package classify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aknow2/beholder/internal/activewin"
+	"github.com/aknow2/beholder/internal/config"
+)
+
+type stubClassifier struct {
+	calls  int
+	result Result
+}
+
+func (s *stubClassifier) Name() string  { return "stub" }
+func (s *stubClassifier) Model() string { return "stub-model" }
+func (s *stubClassifier) Classify(ctx context.Context, screenshots []Screenshot, categories []config.CategoryConfig) (*Result, error) {
+	s.calls++
+	r := s.result
+	return &r, nil
+}
+
+var ruleCategories = []config.CategoryConfig{
+	{ID: "meeting", Name: "会議", Rules: config.CategoryRules{Apps: []string{`(?i)^zoom`}}},
+	{ID: "implement", Name: "実装", Rules: config.CategoryRules{Apps: []string{`^code$`}, Titles: []string{`\.go - `}}},
+	{ID: "research", Name: "調査", Rules: config.CategoryRules{URLs: []string{`github\.com/.*/issues`}}},
+}
+
+func TestRulesMatch(t *testing.T) {
+	r, err := NewRules(nil, nil, ruleCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		info *activewin.Info
+		want string
+	}{
+		{&activewin.Info{Process: "zoom.us", Title: "Zoom Meeting"}, "meeting"},
+		{&activewin.Info{Process: "vim", Title: "main.go - beholder"}, "implement"},
+		{&activewin.Info{Process: "firefox", URL: "https://github.com/aknow2/beholder/issues/3"}, "research"},
+		{&activewin.Info{Process: "firefox", Title: "News"}, ""},
+	}
+	for _, tt := range tests {
+		got := r.Match(tt.info)
+		gotID := ""
+		if got != nil {
+			gotID = got.SelectedCategoryID
+		}
+		if gotID != tt.want {
+			t.Errorf("Match(%+v) = %q, want %q", tt.info, gotID, tt.want)
+		}
+	}
+}
+
+func TestRulesFallback(t *testing.T) {
+	fallback := &stubClassifier{result: Result{SelectedCategoryID: "research"}}
+
+	r, err := NewRules(activewin.Static{Info: &activewin.Info{Process: "code"}}, fallback, ruleCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := r.Classify(context.Background(), nil, ruleCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelectedCategoryID != "implement" || res.Source != SourceRule || fallback.calls != 0 {
+		t.Errorf("expected rule match without fallback, got %+v (calls=%d)", res, fallback.calls)
+	}
+
+	r.Window = activewin.Static{Err: errors.New("no display")}
+	res, err = r.Classify(context.Background(), nil, ruleCategories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SelectedCategoryID != "research" || res.Source != SourceLLM || fallback.calls != 1 {
+		t.Errorf("expected fallback result, got %+v (calls=%d)", res, fallback.calls)
+	}
+}
+
+func TestNewRulesInvalidPattern(t *testing.T) {
+	categories := []config.CategoryConfig{{ID: "implement", Rules: config.CategoryRules{Titles: []string{`(`}}}}
+	if _, err := NewRules(nil, nil, categories); err == nil {
+		t.Error("invalid pattern should fail")
+	}
+}
@@ -15,8 +15,13 @@ var defaultConfig []byte
 type Config struct {
 	Storage    StorageConfig    `yaml:"storage"`
 	Scheduler  SchedulerConfig  `yaml:"scheduler"`
-	Copilot    CopilotConfig    `yaml:"copilot"`
+	Schedule   ScheduleConfig   `yaml:"schedule"`
+	Classifier ClassifierConfig `yaml:"classifier"`
+	Copilot    CopilotConfig    `yaml:"copilot,omitempty"` // Deprecated: use Classifier.Copilot.
 	Image      ImageConfig      `yaml:"image"`
+	Capture    CaptureConfig    `yaml:"capture"`
+	Reclassify ReclassifyConfig `yaml:"reclassify"`
+	Idle       IdleConfig       `yaml:"idle"`
 	Categories []CategoryConfig `yaml:"categories"`
 }
 
@@ -24,12 +29,63 @@ type StorageConfig struct {
 	Path string `yaml:"path"`
 }
 
+// SchedulerConfig picks how captures are spaced. Strategy is fixed (every
+// IntervalMinutes), jitter (IntervalMinutes ± JitterMinutes/2) or adaptive
+// (between MinIntervalMinutes and MaxIntervalMinutes depending on how often
+// the category changes).
 type SchedulerConfig struct {
-	IntervalMinutes int `yaml:"interval_minutes"`
+	IntervalMinutes    int    `yaml:"interval_minutes"`
+	Strategy           string `yaml:"strategy"`
+	JitterMinutes      int    `yaml:"jitter_minutes,omitempty"`
+	MinIntervalMinutes int    `yaml:"min_interval_minutes,omitempty"`
+	MaxIntervalMinutes int    `yaml:"max_interval_minutes,omitempty"`
+	PauseOnLock        bool   `yaml:"pause_on_lock"`
+}
+
+// ScheduleConfig limits recording to working hours. Windows maps a weekday
+// (mon, tue, ... sun) to "HH:MM-HH:MM" ranges in local time; weekdays
+// without an entry are not recorded. Holidays are YYYY-MM-DD dates and
+// Quiet lists one-off periods to skip.
+type ScheduleConfig struct {
+	Enabled  bool                `yaml:"enabled"`
+	Windows  map[string][]string `yaml:"windows"`
+	Holidays []string            `yaml:"holidays,omitempty"`
+	Quiet    []QuietPeriod       `yaml:"quiet,omitempty"`
+}
+
+// QuietPeriod is a local time range written as "YYYY-MM-DD HH:MM".
+type QuietPeriod struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+type ClassifierConfig struct {
+	Provider       string        `yaml:"provider"`
+	TimeoutSeconds int           `yaml:"timeout_seconds"`
+	MaxRetries     int           `yaml:"max_retries"`
+	BackoffSeconds int           `yaml:"backoff_seconds"`
+	Copilot        CopilotConfig `yaml:"copilot,omitempty"`
+	OpenAI         OpenAIConfig  `yaml:"openai,omitempty"`
+	Ollama         OllamaConfig  `yaml:"ollama,omitempty"`
 }
 
 type CopilotConfig struct {
-	Model string `yaml:"model"`
+	Model                string `yaml:"model"`
+	SessionMaxUses       int    `yaml:"session_max_uses"`
+	SessionMaxAgeMinutes int    `yaml:"session_max_age_minutes"`
+}
+
+// OpenAIConfig points at an OpenAI compatible chat completions server.
+// BaseURL includes the version prefix, e.g. http://localhost:8080/v1.
+type OpenAIConfig struct {
+	BaseURL   string `yaml:"base_url"`
+	Model     string `yaml:"model"`
+	APIKeyEnv string `yaml:"api_key_env,omitempty"`
+}
+
+type OllamaConfig struct {
+	BaseURL string `yaml:"base_url,omitempty"`
+	Model   string `yaml:"model"`
 }
 
 type ImageConfig struct {
@@ -37,14 +93,56 @@ type ImageConfig struct {
 	MaxFiles   int    `yaml:"max_files"`
 	SaveImages bool   `yaml:"save_images"`
 	Format     string `yaml:"format"`
+	Quality    int    `yaml:"quality"`
+}
+
+type CaptureConfig struct {
+	Backend     string `yaml:"backend"`
+	DisplayMode string `yaml:"display_mode"`
+	FakeDir     string `yaml:"fake_dir,omitempty"`
+}
+
+// ReclassifyConfig controls retrying classification of FAILED events whose
+// screenshots were kept in the pending queue.
+type ReclassifyConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	IntervalMinutes int  `yaml:"interval_minutes"`
+	MaxAttempts     int  `yaml:"max_attempts"`
+}
+
+// IdleConfig controls skipping capture while the user is away. Once input
+// has been idle for ThresholdMinutes, an event in CategoryID is recorded
+// without taking a screenshot.
+type IdleConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	ThresholdMinutes int    `yaml:"threshold_minutes"`
+	CategoryID       string `yaml:"category_id"`
 }
 
 type CategoryConfig struct {
-	ID          string   `yaml:"id"`
-	Name        string   `yaml:"name"`
-	Description string   `yaml:"description"`
-	Examples    []string `yaml:"examples"`
-	Color       string   `yaml:"color"`
+	ID          string        `yaml:"id"`
+	Name        string        `yaml:"name"`
+	Description string        `yaml:"description"`
+	Examples    []string      `yaml:"examples"`
+	Color       string        `yaml:"color"`
+	Rules       CategoryRules `yaml:"rules,omitempty" json:"-"`
+}
+
+// CategoryRules are regular expressions matched against the focused window.
+// A category whose rule matches is chosen without asking the classifier.
+type CategoryRules struct {
+	Apps   []string `yaml:"apps,omitempty"`   // process or window class name
+	Titles []string `yaml:"titles,omitempty"` // window title
+	URLs   []string `yaml:"urls,omitempty"`   // browser URL, or the title when the URL is unknown
+}
+
+// CategoryIDs maps each category name to its id.
+func (c *Config) CategoryIDs() map[string]string {
+	ids := make(map[string]string, len(c.Categories))
+	for _, cat := range c.Categories {
+		ids[cat.Name] = cat.ID
+	}
+	return ids
 }
 
 func Load(path string) (*Config, error) {
@@ -70,10 +168,21 @@ func Load(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
+	migrateLegacy(&cfg)
 
 	return &cfg, nil
 }
 
+// migrateLegacy maps the top-level copilot section used before classifier
+// providers existed onto classifier.copilot.
+func migrateLegacy(cfg *Config) {
+	if cfg.Classifier.Provider == "" && cfg.Copilot.Model != "" {
+		cfg.Classifier.Provider = "copilot"
+		cfg.Classifier.Copilot = cfg.Copilot
+	}
+	cfg.Copilot = CopilotConfig{}
+}
+
 func ResolvePath(path string) (string, error) {
 	// Expand ~ to home directory
 	if len(path) > 0 && path[0] == '~' {
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadLegacyCopilotSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("storage:\n  path: test.db\ncopilot:\n  model: gpt-4.1\n")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Classifier.Provider != "copilot" || cfg.Classifier.Copilot.Model != "gpt-4.1" {
+		t.Errorf("legacy copilot section not migrated: %+v", cfg.Classifier)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed.Scheduler.IntervalMinutes = 5
+	changed.Categories = append(changed.Categories[1:], CategoryConfig{ID: "review", Name: "レビュー"})
+
+	diff, err := Diff(old, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{
+		"scheduler.interval_minutes: 10 -> 5":                                                      true,
+		"categories[review].id: (unset) -> review":                                                 true,
+		"categories[review].name: (unset) -> レビュー":                                                 true,
+		"categories[" + old.Categories[0].ID + "].name: " + old.Categories[0].Name + " -> (unset)": true,
+	}
+	for w := range want {
+		found := false
+		for _, d := range diff {
+			if d == w {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("diff is missing %q:\n%s", w, strings.Join(diff, "\n"))
+		}
+	}
+	for _, d := range diff {
+		if strings.HasPrefix(d, "categories["+old.Categories[1].ID+"]") {
+			t.Errorf("reordered category should not show as changed: %s", d)
+		}
+	}
+
+	if diff, _ := Diff(old, old); len(diff) != 0 {
+		t.Errorf("identical configs differ: %v", diff)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diff lists the settings that differ between old and new, one
+// "path: old -> new" line per leaf value. Entries of lists whose items have
+// an id, such as categories, are matched by id rather than position.
+func Diff(old, new *Config) ([]string, error) {
+	before, err := flattenConfig(old)
+	if err != nil {
+		return nil, err
+	}
+	after, err := flattenConfig(new)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]struct{}{}
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, k := range sorted {
+		b, inBefore := before[k]
+		a, inAfter := after[k]
+		if inBefore && inAfter && a == b {
+			continue
+		}
+		if !inBefore {
+			b = "(unset)"
+		}
+		if !inAfter {
+			a = "(unset)"
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", k, b, a))
+	}
+	return changes, nil
+}
+
+func flattenConfig(cfg *Config) (map[string]string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	flatten("", tree, out)
+	return out, nil
+}
+
+func flatten(prefix string, v any, out map[string]string) {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, child := range x {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(key, child, out)
+		}
+	case []any:
+		if len(x) == 0 {
+			out[prefix] = "[]"
+			return
+		}
+		for i, item := range x {
+			label := fmt.Sprint(i)
+			if m, ok := item.(map[string]any); ok {
+				if id, ok := m["id"].(string); ok && id != "" {
+					label = id
+				}
+			}
+			flatten(fmt.Sprintf("%s[%s]", prefix, label), item, out)
+		}
+	default:
+		out[prefix] = strings.TrimSpace(fmt.Sprint(x))
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DateLayout      = "2006-01-02"
+	QuietTimeLayout = "2006-01-02 15:04"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWeekday parses a schedule.windows key, mon..sun.
+func ParseWeekday(s string) (time.Weekday, error) {
+	wd, ok := weekdays[strings.ToLower(s)]
+	if !ok {
+		return 0, fmt.Errorf("unknown weekday %q, want mon..sun", s)
+	}
+	return wd, nil
+}
+
+// ParseWindow parses "HH:MM-HH:MM" into minutes since local midnight, end
+// exclusive. The end may be 24:00 and must be after the start.
+func ParseWindow(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("window %q must be HH:MM-HH:MM", s)
+	}
+	if start, err = parseClock(strings.TrimSpace(from)); err != nil {
+		return 0, 0, fmt.Errorf("window %q: %w", s, err)
+	}
+	if end, err = parseClock(strings.TrimSpace(to)); err != nil {
+		return 0, 0, fmt.Errorf("window %q: %w", s, err)
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("window %q ends before it starts", s)
+	}
+	return start, end, nil
+}
+
+func parseClock(s string) (int, error) {
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Parse reads the period as local times in loc. The end must be after the
+// start.
+func (q QuietPeriod) Parse(loc *time.Location) (start, end time.Time, err error) {
+	if start, err = time.ParseInLocation(QuietTimeLayout, q.Start, loc); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start: %w", err)
+	}
+	if end, err = time.ParseInLocation(QuietTimeLayout, q.End, loc); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end: %w", err)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end %s is not after start %s", q.End, q.Start)
+	}
+	return start, end, nil
+}
@@ -1,6 +1,12 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
 
 func Validate(cfg *Config) error {
 	if cfg == nil {
@@ -9,8 +15,8 @@ func Validate(cfg *Config) error {
 	if cfg.Storage.Path == "" {
 		return fmt.Errorf("storage.path is required")
 	}
-	if cfg.Copilot.Model == "" {
-		return fmt.Errorf("copilot.model is required")
+	if err := validateClassifier(cfg); err != nil {
+		return err
 	}
 	if len(cfg.Categories) == 0 {
 		return fmt.Errorf("categories must contain at least one entry")
@@ -26,6 +32,39 @@ func Validate(cfg *Config) error {
 	if cfg.Image.Format != "jpeg" && cfg.Image.Format != "png" {
 		return fmt.Errorf("image.format must be 'jpeg' or 'png', got: %s", cfg.Image.Format)
 	}
+	if cfg.Image.Quality < 0 || cfg.Image.Quality > 100 {
+		return fmt.Errorf("image.quality must be between 1 and 100 (0 uses the default), got: %d", cfg.Image.Quality)
+	}
+
+	switch cfg.Capture.Backend {
+	case "", "auto", "screencapture", "x11", "wayland", "portal", "gdi":
+	case "fake":
+		if cfg.Capture.FakeDir == "" {
+			return fmt.Errorf("capture.fake_dir is required for the fake backend")
+		}
+	default:
+		return fmt.Errorf("capture.backend must be one of auto, screencapture, x11, wayland, portal, gdi, fake, got: %s", cfg.Capture.Backend)
+	}
+
+	if cfg.Capture.DisplayMode != "" && cfg.Capture.DisplayMode != "composite" && cfg.Capture.DisplayMode != "separate" {
+		return fmt.Errorf("capture.display_mode must be 'composite' or 'separate', got: %s", cfg.Capture.DisplayMode)
+	}
+
+	if err := validateScheduler(cfg.Scheduler); err != nil {
+		return err
+	}
+	if err := validateSchedule(cfg.Schedule); err != nil {
+		return err
+	}
+
+	if cfg.Reclassify.Enabled {
+		if cfg.Reclassify.IntervalMinutes <= 0 {
+			return fmt.Errorf("reclassify.interval_minutes must be > 0, got: %d", cfg.Reclassify.IntervalMinutes)
+		}
+		if cfg.Reclassify.MaxAttempts <= 0 {
+			return fmt.Errorf("reclassify.max_attempts must be > 0, got: %d", cfg.Reclassify.MaxAttempts)
+		}
+	}
 
 	ids := map[string]struct{}{}
 	for _, c := range cfg.Categories {
@@ -36,7 +75,121 @@ func Validate(cfg *Config) error {
 			return fmt.Errorf("duplicate category id: %s", c.ID)
 		}
 		ids[c.ID] = struct{}{}
+
+		if c.Color != "" && !colorPattern.MatchString(c.Color) {
+			return fmt.Errorf("category %s: color must be a hex color like #4e79a7, got: %s", c.ID, c.Color)
+		}
+
+		for _, patterns := range [][]string{c.Rules.Apps, c.Rules.Titles, c.Rules.URLs} {
+			for _, p := range patterns {
+				if _, err := regexp.Compile(p); err != nil {
+					return fmt.Errorf("category %s: invalid rule pattern %q: %w", c.ID, p, err)
+				}
+			}
+		}
+	}
+
+	if cfg.Idle.Enabled && cfg.Idle.ThresholdMinutes <= 0 {
+		return fmt.Errorf("idle.threshold_minutes must be > 0, got: %d", cfg.Idle.ThresholdMinutes)
+	}
+	// Lock and sleep spans are filed under the idle category too.
+	if cfg.Idle.Enabled || cfg.Scheduler.PauseOnLock {
+		if _, ok := ids[cfg.Idle.CategoryID]; !ok {
+			return fmt.Errorf("idle.category_id must be one of the category ids, got: %s", cfg.Idle.CategoryID)
+		}
+	}
+
+	return nil
+}
+
+func validateScheduler(s SchedulerConfig) error {
+	if s.IntervalMinutes <= 0 {
+		return fmt.Errorf("scheduler.interval_minutes must be > 0, got: %d", s.IntervalMinutes)
 	}
+	switch s.Strategy {
+	case "", "fixed":
+	case "jitter":
+		if s.JitterMinutes < 0 {
+			return fmt.Errorf("scheduler.jitter_minutes must be >= 0, got: %d", s.JitterMinutes)
+		}
+	case "adaptive":
+		if s.MinIntervalMinutes < 0 {
+			return fmt.Errorf("scheduler.min_interval_minutes must be >= 0, got: %d", s.MinIntervalMinutes)
+		}
+		if s.MaxIntervalMinutes != 0 && s.MaxIntervalMinutes < s.MinIntervalMinutes {
+			return fmt.Errorf("scheduler.max_interval_minutes must be >= min_interval_minutes, got: %d", s.MaxIntervalMinutes)
+		}
+	default:
+		return fmt.Errorf("scheduler.strategy must be one of fixed, jitter, adaptive, got: %s", s.Strategy)
+	}
+	return nil
+}
 
+// validateSchedule parses the schedule the same way the scheduler's
+// calendar does, so a config that validates also starts.
+func validateSchedule(s ScheduleConfig) error {
+	for day, ranges := range s.Windows {
+		if _, err := ParseWeekday(day); err != nil {
+			return fmt.Errorf("schedule.windows: %w", err)
+		}
+		for _, r := range ranges {
+			if _, _, err := ParseWindow(r); err != nil {
+				return fmt.Errorf("schedule.windows.%s: %w", day, err)
+			}
+		}
+	}
+	for _, h := range s.Holidays {
+		if _, err := time.Parse(DateLayout, h); err != nil {
+			return fmt.Errorf("schedule.holidays must be YYYY-MM-DD, got: %s", h)
+		}
+	}
+	for _, q := range s.Quiet {
+		if _, _, err := q.Parse(time.Local); err != nil {
+			return fmt.Errorf("schedule.quiet: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateClassifier(cfg *Config) error {
+	c := cfg.Classifier
+	if c.TimeoutSeconds < 0 {
+		return fmt.Errorf("classifier.timeout_seconds must be >= 0, got: %d", c.TimeoutSeconds)
+	}
+	if c.MaxRetries < 0 || c.MaxRetries > 10 {
+		return fmt.Errorf("classifier.max_retries must be between 0 and 10, got: %d", c.MaxRetries)
+	}
+	if c.BackoffSeconds < 0 {
+		return fmt.Errorf("classifier.backoff_seconds must be >= 0, got: %d", c.BackoffSeconds)
+	}
+
+	switch c.Provider {
+	case "":
+		// Load has already moved a legacy top-level copilot section here.
+		return fmt.Errorf("classifier.provider is required")
+	case "copilot":
+		if c.Copilot.Model == "" {
+			return fmt.Errorf("classifier.copilot.model is required")
+		}
+		if c.Copilot.SessionMaxUses < 0 {
+			return fmt.Errorf("classifier.copilot.session_max_uses must be >= 0, got: %d", c.Copilot.SessionMaxUses)
+		}
+		if c.Copilot.SessionMaxAgeMinutes < 0 {
+			return fmt.Errorf("classifier.copilot.session_max_age_minutes must be >= 0, got: %d", c.Copilot.SessionMaxAgeMinutes)
+		}
+	case "openai":
+		if c.OpenAI.BaseURL == "" {
+			return fmt.Errorf("classifier.openai.base_url is required")
+		}
+		if c.OpenAI.Model == "" {
+			return fmt.Errorf("classifier.openai.model is required")
+		}
+	case "ollama":
+		if c.Ollama.Model == "" {
+			return fmt.Errorf("classifier.ollama.model is required")
+		}
+	default:
+		return fmt.Errorf("classifier.provider must be one of copilot, openai, ollama, got: %s", c.Provider)
+	}
 	return nil
 }
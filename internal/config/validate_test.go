@@ -5,21 +5,123 @@ import "testing"
 func TestValidateValid(t *testing.T) {
 	cfg := &Config{
 		Storage:    StorageConfig{Path: "test.db"},
-		Copilot:    CopilotConfig{Model: "gpt-4.1"},
+		Classifier: ClassifierConfig{Provider: "copilot", Copilot: CopilotConfig{Model: "gpt-4.1"}},
+		Scheduler:  SchedulerConfig{IntervalMinutes: 10},
+		Image:      ImageConfig{MaxWidth: 1280, Format: "jpeg"},
 		Categories: []CategoryConfig{{ID: "test", Name: "Test"}},
 	}
 	if err := Validate(cfg); err != nil {
 		t.Errorf("valid config should not error: %v", err)
 	}
+
+	cfg.Classifier.Provider = ""
+	if err := Validate(cfg); err == nil {
+		t.Error("empty classifier.provider should error")
+	}
+	cfg.Classifier.Provider = "copilot"
+
+	cfg.Scheduler.IntervalMinutes = 0
+	if err := Validate(cfg); err == nil {
+		t.Error("zero scheduler.interval_minutes should error")
+	}
 }
 
 func TestValidateEmptyPath(t *testing.T) {
 	cfg := &Config{
 		Storage:    StorageConfig{Path: ""},
-		Copilot:    CopilotConfig{Model: "gpt-4.1"},
+		Classifier: ClassifierConfig{Provider: "copilot", Copilot: CopilotConfig{Model: "gpt-4.1"}},
 		Categories: []CategoryConfig{{ID: "test", Name: "Test"}},
 	}
 	if err := Validate(cfg); err == nil {
 		t.Error("empty path should error")
 	}
 }
+
+func TestValidateCaptureBackend(t *testing.T) {
+	cfg, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Capture.Backend = "vnc"
+	if err := Validate(cfg); err == nil {
+		t.Error("unknown backend should error")
+	}
+	cfg.Capture.Backend = "fake"
+	if err := Validate(cfg); err == nil {
+		t.Error("fake backend without fake_dir should error")
+	}
+}
+
+func TestValidateClassifierProvider(t *testing.T) {
+	cfg, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Classifier = ClassifierConfig{Provider: "openai", OpenAI: OpenAIConfig{Model: "llava"}}
+	if err := Validate(cfg); err == nil {
+		t.Error("openai without base_url should error")
+	}
+	cfg.Classifier.OpenAI.BaseURL = "http://localhost:8080/v1"
+	if err := Validate(cfg); err != nil {
+		t.Errorf("openai config should be valid: %v", err)
+	}
+}
+
+func TestValidateIdleCategory(t *testing.T) {
+	cfg, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Idle.CategoryID = "away"
+	if err := Validate(cfg); err == nil {
+		t.Error("idle category that is not configured should error")
+	}
+	cfg.Idle.Enabled = false
+	if err := Validate(cfg); err == nil {
+		t.Error("pause_on_lock records spans in the idle category, it should be checked")
+	}
+	cfg.Scheduler.PauseOnLock = false
+	if err := Validate(cfg); err != nil {
+		t.Errorf("idle category unused, it should not be checked: %v", err)
+	}
+}
+
+func TestValidateCategoryColor(t *testing.T) {
+	cfg, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Categories[0].Color = "#abc"
+	if err := Validate(cfg); err != nil {
+		t.Errorf("short hex color should be accepted: %v", err)
+	}
+	cfg.Categories[0].Color = "blue;background:url(x)"
+	if err := Validate(cfg); err == nil {
+		t.Error("non-hex color should error")
+	}
+}
+
+func TestValidateScheduleOrder(t *testing.T) {
+	cfg, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Schedule.Windows = map[string][]string{"mon": {"18:00-09:00"}}
+	if err := Validate(cfg); err == nil {
+		t.Error("inverted window should error")
+	}
+
+	cfg.Schedule.Windows = map[string][]string{"mon": {"09:00-24:00"}}
+	cfg.Schedule.Quiet = []QuietPeriod{{Start: "2026-03-30 11:00", End: "2026-03-30 10:00"}}
+	if err := Validate(cfg); err == nil {
+		t.Error("quiet period ending before it starts should error")
+	}
+	cfg.Schedule.Quiet[0].End = cfg.Schedule.Quiet[0].Start
+	if err := Validate(cfg); err == nil {
+		t.Error("empty quiet period should error")
+	}
+	cfg.Schedule.Quiet[0].End = "2026-03-30 12:00"
+	if err := Validate(cfg); err != nil {
+		t.Errorf("valid schedule should not error: %v", err)
+	}
+}
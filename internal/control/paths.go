@@ -0,0 +1,64 @@
+package control
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNotRunning means no daemon answered on the control socket.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// ErrAlreadyRunning means another daemon answered on the control socket.
+var ErrAlreadyRunning = errors.New("daemon is already running")
+
+// Paths are the files a daemon uses under ~/.beholder.
+type Paths struct {
+	PIDFile string
+	Socket  string
+	LogFile string
+}
+
+func DefaultPaths() (Paths, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return Paths{}, err
+	}
+	dir := filepath.Join(homeDir, ".beholder")
+	return Paths{
+		PIDFile: filepath.Join(dir, "beholder.pid"),
+		Socket:  filepath.Join(dir, "beholder.sock"),
+		LogFile: filepath.Join(dir, "daemon.log"),
+	}, nil
+}
+
+func WritePID(path string, pid int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0644)
+}
+
+// ReadPID returns 0 without error when there is no PID file.
+func ReadPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// RemovePID removes the PID file if it still names pid, so a daemon that
+// exits late cannot delete its replacement's file.
+func RemovePID(path string, pid int) error {
+	current, err := ReadPID(path)
+	if err != nil || current != pid {
+		return err
+	}
+	return os.Remove(path)
+}
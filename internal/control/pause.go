@@ -0,0 +1,70 @@
+// Package control lets CLI commands steer a running recorder.
+package control
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// indefinite is written instead of a time when pausing until resumed.
+const indefinite = "indefinite"
+
+// PauseFile is the path of the control file written by `beholder pause`.
+// The recorder checks it before every capture, so no signal has to reach
+// the running process.
+type PauseFile string
+
+func DefaultPauseFile() (PauseFile, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return PauseFile(filepath.Join(homeDir, ".beholder", "pause")), nil
+}
+
+// Pause stops recording until the given time, or until Resume when until is
+// zero.
+func (p PauseFile) Pause(until time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(string(p)), 0755); err != nil {
+		return err
+	}
+	content := indefinite
+	if !until.IsZero() {
+		content = until.UTC().Format(time.RFC3339)
+	}
+	return os.WriteFile(string(p), []byte(content+"\n"), 0644)
+}
+
+// Resume removes the pause. It is not an error if recording was not paused.
+func (p PauseFile) Resume() error {
+	if err := os.Remove(string(p)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// PausedUntil reports whether recording is paused at now. until is zero for
+// an indefinite pause. An expired pause counts as not paused.
+func (p PauseFile) PausedUntil(now time.Time) (until time.Time, paused bool, err error) {
+	data, err := os.ReadFile(string(p))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == indefinite {
+		return time.Time{}, true, nil
+	}
+	until, err = time.Parse(time.RFC3339, content)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("parse %s: %w", p, err)
+	}
+	return until, now.Before(until), nil
+}
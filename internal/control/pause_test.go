@@ -0,0 +1,44 @@
+package control
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPauseFile(t *testing.T) {
+	p := PauseFile(filepath.Join(t.TempDir(), "pause"))
+	now := time.Date(2026, 1, 28, 12, 0, 0, 0, time.UTC)
+
+	if _, paused, err := p.PausedUntil(now); err != nil || paused {
+		t.Fatalf("no file: paused=%v err=%v", paused, err)
+	}
+
+	if err := p.Pause(now.Add(30 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	until, paused, err := p.PausedUntil(now)
+	if err != nil || !paused || !until.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("timed pause: until=%v paused=%v err=%v", until, paused, err)
+	}
+	if _, paused, _ := p.PausedUntil(now.Add(time.Hour)); paused {
+		t.Error("pause should expire")
+	}
+
+	if err := p.Pause(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if until, paused, _ := p.PausedUntil(now.AddDate(1, 0, 0)); !paused || !until.IsZero() {
+		t.Errorf("indefinite pause: until=%v paused=%v", until, paused)
+	}
+
+	if err := p.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Resume(); err != nil {
+		t.Errorf("second resume should be a no-op: %v", err)
+	}
+	if _, paused, _ := p.PausedUntil(now); paused {
+		t.Error("still paused after resume")
+	}
+}
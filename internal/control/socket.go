@@ -0,0 +1,225 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	CommandStatus  = "status"
+	CommandCapture = "capture"
+	CommandPause   = "pause"
+	CommandResume  = "resume"
+	CommandReload  = "reload"
+	CommandStop    = "stop"
+)
+
+// Request is one command sent to the daemon. Duration is only used by
+// pause, in time.ParseDuration format; empty means until resumed.
+type Request struct {
+	Command  string `json:"command"`
+	Duration string `json:"duration,omitempty"`
+}
+
+type Response struct {
+	OK      bool    `json:"ok"`
+	Error   string  `json:"error,omitempty"`
+	Status  *Status `json:"status,omitempty"`
+	Message string  `json:"message,omitempty"`
+}
+
+// Status describes a running daemon. Zero times mean "never" or "unknown".
+type Status struct {
+	PID          int       `json:"pid"`
+	StartedAt    time.Time `json:"started_at"`
+	ConfigPath   string    `json:"config_path"`
+	LastCapture  time.Time `json:"last_capture,omitempty"`
+	LastStatus   string    `json:"last_status,omitempty"`
+	LastCategory string    `json:"last_category,omitempty"`
+	LastError    string    `json:"last_error,omitempty"`
+	NextRun      time.Time `json:"next_run,omitempty"`
+	Paused       bool      `json:"paused"`
+	PausedUntil  time.Time `json:"paused_until,omitempty"`
+}
+
+// Handler carries out requests on behalf of Serve.
+type Handler interface {
+	Status() Status
+	// Capture records an event immediately and describes the result.
+	Capture(ctx context.Context) (string, error)
+	Pause(d time.Duration) error
+	Resume() error
+	Reload() error
+	Stop()
+}
+
+// Listen creates the control socket at path. It fails with
+// ErrAlreadyRunning when another daemon answers there, and replaces a
+// socket left behind by one that crashed. Closing the listener removes the
+// socket.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return nil, ErrAlreadyRunning
+	}
+
+	// Bind inside a private directory and move the socket into place once
+	// it is 0600, so nobody else can connect in between.
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp(dir, ".sock-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmp := filepath.Join(tmpDir, "s")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0600); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return &socketListener{Listener: l, path: path}, nil
+}
+
+type socketListener struct {
+	net.Listener
+	path string
+	once sync.Once
+}
+
+func (l *socketListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		err = l.Listener.Close()
+		if rerr := os.Remove(l.path); rerr != nil && !errors.Is(rerr, os.ErrNotExist) && err == nil {
+			err = rerr
+		}
+	})
+	return err
+}
+
+// Serve accepts connections on l, see Listen, until ctx is cancelled, and
+// closes l when done. Each connection carries one JSON request and one
+// JSON response.
+func Serve(ctx context.Context, l net.Listener, h Handler) error {
+	defer l.Close()
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go serveConn(ctx, conn, h)
+	}
+}
+
+func serveConn(ctx context.Context, conn net.Conn, h Handler) {
+	defer conn.Close()
+
+	var req Request
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+	var resp Response
+	if err != nil {
+		resp.Error = fmt.Sprintf("bad request: %v", err)
+	} else {
+		resp = handle(ctx, req, h)
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		log.Printf("control: failed to write response: %v", err)
+	}
+}
+
+func handle(ctx context.Context, req Request, h Handler) Response {
+	var err error
+	var resp Response
+	switch req.Command {
+	case CommandStatus:
+		status := h.Status()
+		resp.Status = &status
+	case CommandCapture:
+		resp.Message, err = h.Capture(ctx)
+	case CommandPause:
+		var d time.Duration
+		if req.Duration != "" {
+			d, err = time.ParseDuration(req.Duration)
+		}
+		if err == nil {
+			err = h.Pause(d)
+		}
+	case CommandResume:
+		err = h.Resume()
+	case CommandReload:
+		err = h.Reload()
+	case CommandStop:
+		h.Stop()
+	default:
+		err = fmt.Errorf("unknown command: %s", req.Command)
+	}
+
+	if err != nil {
+		return Response{Error: err.Error()}
+	}
+	resp.OK = true
+	return resp
+}
+
+// Call sends req to the daemon listening at path. A daemon that is not
+// running shows up as an error wrapping ErrNotRunning.
+func Call(path string, req Request, timeout time.Duration) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotRunning, err)
+	}
+	defer conn.Close()
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if !resp.OK {
+		return &resp, errors.New(resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,101 @@
+package control
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeHandler struct {
+	paused  time.Duration
+	stopped bool
+}
+
+func (h *fakeHandler) Status() Status { return Status{PID: 42, LastError: "timeout"} }
+func (h *fakeHandler) Capture(ctx context.Context) (string, error) {
+	return "recorded", nil
+}
+func (h *fakeHandler) Pause(d time.Duration) error { h.paused = d; return nil }
+func (h *fakeHandler) Resume() error               { return nil }
+func (h *fakeHandler) Reload() error               { return errors.New("invalid config") }
+func (h *fakeHandler) Stop()                       { h.stopped = true }
+
+func TestServe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.sock")
+	h := &fakeHandler{}
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, l, h) }()
+
+	var resp *Response
+	for i := 0; i < 50; i++ {
+		resp, err = Call(path, Request{Command: CommandStatus}, time.Second)
+		if !errors.Is(err, ErrNotRunning) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status.PID != 42 || resp.Status.LastError != "timeout" {
+		t.Errorf("status = %+v", resp.Status)
+	}
+
+	if resp, err := Call(path, Request{Command: CommandCapture}, time.Second); err != nil || resp.Message != "recorded" {
+		t.Errorf("capture = %+v, %v", resp, err)
+	}
+	if _, err := Call(path, Request{Command: CommandPause, Duration: "30m"}, time.Second); err != nil || h.paused != 30*time.Minute {
+		t.Errorf("pause: paused=%v err=%v", h.paused, err)
+	}
+	if _, err := Call(path, Request{Command: CommandReload}, time.Second); err == nil || err.Error() != "invalid config" {
+		t.Errorf("reload error = %v", err)
+	}
+	if _, err := Call(path, Request{Command: "explode"}, time.Second); err == nil {
+		t.Error("unknown command should error")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Call(path, Request{Command: CommandStatus}, time.Second); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("after shutdown err = %v, want ErrNotRunning", err)
+	}
+}
+
+func TestListen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.sock")
+
+	// A socket left behind by a crashed daemon is replaced.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatalf("stale socket: %v", err)
+	}
+	defer l.Close()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode = %v, %v", info.Mode().Perm(), err)
+	}
+
+	if _, err := Listen(path); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("second Listen = %v, want ErrAlreadyRunning", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("running daemon's socket removed: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+const (
+	FormatTable  = "table"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+)
+
+// Formats lists every supported output format.
+var Formats = []string{FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatTSV}
+
+// Record is the exported form of a storage.Event. Field names are part of
+// the output format and must not change; add new fields at the end.
+type Record struct {
+	ID                 string                `json:"id"`
+	CapturedAt         string                `json:"captured_at"`
+	CategoryID         string                `json:"category_id"`
+	CategoryName       string                `json:"category_name"`
+	Confidence         float64               `json:"confidence"`
+	Status             string                `json:"status"`
+	AgentVersion       string                `json:"agent_version"`
+	ScreenshotHash     string                `json:"screenshot_hash"`
+	DetectedApps       []string              `json:"detected_apps"`
+	DetectedKeywords   []string              `json:"detected_keywords"`
+	ClassifiedBy       string                `json:"classified_by"`
+	ClassifierProvider string                `json:"classifier_provider"`
+	Rationale          string                `json:"rationale"`
+	ErrorKind          string                `json:"error_kind"`
+	ErrorMessage       string                `json:"error_message"`
+	ClassifyAttempts   int                   `json:"classify_attempts"`
+	DisplayCount       int                   `json:"display_count"`
+	Displays           []storage.DisplayInfo `json:"displays"`
+	Resolution         string                `json:"resolution"`
+	Notes              string                `json:"notes"`
+	CreatedAt          string                `json:"created_at"`
+	SpanEnd            string                `json:"span_end"`
+	IdleSeconds        int                   `json:"idle_seconds"`
+}
+
+// columns are the CSV and TSV header, in Record field order.
+var columns = []string{
+	"id", "captured_at", "category_id", "category_name", "confidence", "status", "agent_version", "screenshot_hash",
+	"detected_apps", "detected_keywords", "classified_by", "classifier_provider", "rationale", "error_kind", "error_message",
+	"classify_attempts", "display_count", "displays", "resolution", "notes", "created_at",
+	"span_end", "idle_seconds",
+}
+
+// listSeparator joins detected apps and keywords in CSV and TSV cells.
+const listSeparator = ";"
+
+func NewRecord(e storage.Event) Record {
+	r := Record{
+		ID:                 e.ID,
+		CapturedAt:         e.CapturedAt.UTC().Format(time.RFC3339),
+		CategoryID:         e.CategoryID,
+		CategoryName:       e.CategoryName,
+		Confidence:         e.Confidence,
+		Status:             e.Status,
+		AgentVersion:       e.AgentVersion,
+		ScreenshotHash:     e.ScreenshotHash,
+		DetectedApps:       e.DetectedApps,
+		DetectedKeywords:   e.DetectedKeywords,
+		ClassifiedBy:       e.ClassifiedBy,
+		ClassifierProvider: e.ClassifierProvider,
+		Rationale:          e.Rationale,
+		ErrorKind:          e.ErrorKind,
+		ErrorMessage:       e.ErrorMessage,
+		ClassifyAttempts:   e.ClassifyAttempts,
+		DisplayCount:       e.DisplayCount,
+		Displays:           e.Displays,
+		Resolution:         e.Resolution,
+		Notes:              e.Notes,
+		CreatedAt:          e.CreatedAt.UTC().Format(time.RFC3339),
+		IdleSeconds:        e.IdleSeconds,
+	}
+	if !e.SpanEnd.IsZero() {
+		r.SpanEnd = e.SpanEnd.UTC().Format(time.RFC3339)
+	}
+	// Always emit arrays so consumers never see null.
+	if r.DetectedApps == nil {
+		r.DetectedApps = []string{}
+	}
+	if r.DetectedKeywords == nil {
+		r.DetectedKeywords = []string{}
+	}
+	if r.Displays == nil {
+		r.Displays = []storage.DisplayInfo{}
+	}
+	return r
+}
+
+func (r Record) row() []string {
+	displays, _ := json.Marshal(r.Displays)
+	return []string{
+		r.ID, r.CapturedAt, r.CategoryID, r.CategoryName, strconv.FormatFloat(r.Confidence, 'f', -1, 64), r.Status, r.AgentVersion, r.ScreenshotHash,
+		strings.Join(r.DetectedApps, listSeparator), strings.Join(r.DetectedKeywords, listSeparator), r.ClassifiedBy, r.ClassifierProvider, r.Rationale, r.ErrorKind, r.ErrorMessage,
+		strconv.Itoa(r.ClassifyAttempts), strconv.Itoa(r.DisplayCount), string(displays), r.Resolution, r.Notes, r.CreatedAt,
+		r.SpanEnd, strconv.Itoa(r.IdleSeconds),
+	}
+}
+
+// Write writes events to w in format.
+func Write(w io.Writer, format string, events []storage.Event) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, events)
+	case FormatJSON:
+		records := make([]Record, 0, len(events))
+		for _, e := range events {
+			records = append(records, NewRecord(e))
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, e := range events {
+			if err := enc.Encode(NewRecord(e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := cw.Write(NewRecord(e).row()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatTSV:
+		return writeTSV(w, events)
+	default:
+		return fmt.Errorf("unknown output format: %s (want one of %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// writeTSV writes unquoted tab-separated values. Tabs and line breaks
+// inside a value become spaces so every event stays on one line.
+func writeTSV(w io.Writer, events []storage.Event) error {
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	if _, err := fmt.Fprintln(w, strings.Join(columns, "\t")); err != nil {
+		return err
+	}
+	for _, e := range events {
+		row := NewRecord(e).row()
+		for i, v := range row {
+			row[i] = clean.Replace(v)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, events []storage.Event) error {
+	if len(events) == 0 {
+		_, err := fmt.Fprintln(w, "no events")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tCATEGORY\tCONFIDENCE\tSTATUS\tERROR")
+	for _, e := range events {
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%s\n", e.CapturedAt.Local().Format("2006-01-02 15:04"), e.CategoryName, e.Confidence, e.Status, e.ErrorKind)
+	}
+	return tw.Flush()
+}
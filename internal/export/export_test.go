@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+var testEvents = []storage.Event{
+	{
+		ID: "e1", CapturedAt: time.Date(2026, 1, 28, 1, 0, 0, 0, time.UTC), CategoryID: "implement", CategoryName: "実装",
+		Confidence: 0.9, Status: "OK", DetectedApps: []string{"code", "firefox"}, ClassifiedBy: "llm", ClassifierProvider: "copilot",
+		Rationale: "editing\tmain.go,\nthen \"tests\"", ClassifyAttempts: 1, DisplayCount: 1,
+		Displays: []storage.DisplayInfo{{Width: 1920, Height: 1080, Primary: true}}, Resolution: "1920x1080",
+		CreatedAt: time.Date(2026, 1, 28, 1, 0, 0, 0, time.UTC),
+	},
+	{ID: "e2", CapturedAt: time.Date(2026, 1, 28, 1, 10, 0, 0, time.UTC), Status: "FAILED", ErrorKind: "timeout"},
+}
+
+func TestColumnsMatchRecord(t *testing.T) {
+	typ := reflect.TypeOf(Record{})
+	if typ.NumField() != len(columns) {
+		t.Fatalf("%d columns for %d fields", len(columns), typ.NumField())
+	}
+	for i := range columns {
+		if tag := typ.Field(i).Tag.Get("json"); tag != columns[i] {
+			t.Errorf("column %d = %q, json tag %q", i, columns[i], tag)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, testEvents); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0]["category_id"] != "implement" || got[0]["captured_at"] != "2026-01-28T01:00:00Z" {
+		t.Errorf("unexpected records: %v", got)
+	}
+	if apps, ok := got[1]["detected_apps"].([]any); !ok || len(apps) != 0 {
+		t.Errorf("missing apps should be an empty array, got %v", got[1]["detected_apps"])
+	}
+
+	buf.Reset()
+	if err := Write(&buf, FormatJSON, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("no events: %q, %v", buf.String(), err)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatNDJSON, testEvents); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, got %q", buf.String())
+	}
+	var r Record
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil || r.ID != "e2" || r.ErrorKind != "timeout" {
+		t.Errorf("line 2 = %+v, %v", r, err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, testEvents); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || !reflect.DeepEqual(rows[0], columns) {
+		t.Fatalf("unexpected rows: %q", rows)
+	}
+	if rows[1][8] != "code;firefox" || rows[1][12] != testEvents[0].Rationale || rows[1][17] != `[{"index":0,"x":0,"y":0,"width":1920,"height":1080,"primary":true}]` {
+		t.Errorf("row 1 = %q", rows[1])
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatTSV, testEvents); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, got %q", buf.String())
+	}
+	for i, line := range lines {
+		if n := len(strings.Split(line, "\t")); n != len(columns) {
+			t.Errorf("line %d has %d fields", i, n)
+		}
+	}
+	if !strings.Contains(lines[1], "\tediting main.go, then \"tests\"\t") {
+		t.Errorf("rationale not flattened: %q", lines[1])
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xml", testEvents); err == nil {
+		t.Error("unknown format should error")
+	}
+}
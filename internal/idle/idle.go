@@ -0,0 +1,47 @@
+package idle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"time"
+)
+
+// ErrUnsupported is returned by sources that cannot query the idle time on
+// the current platform or session.
+var ErrUnsupported = errors.New("idle time lookup is not supported here")
+
+// Source reports how long the user has been away from keyboard and mouse.
+type Source interface {
+	Idle(ctx context.Context) (time.Duration, error)
+}
+
+// New returns the best Source for the current session. X11 reports input
+// idle time directly; elsewhere on Linux logind's IdleHint is used, which
+// the desktop sets once its own idle timeout expires.
+func New() Source {
+	if runtime.GOOS != "linux" {
+		return unsupported{}
+	}
+	if os.Getenv("DISPLAY") != "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return X11{}
+	}
+	return Logind{}
+}
+
+// Static always reports the same idle time. It is meant for tests.
+type Static struct {
+	Duration time.Duration
+	Err      error
+}
+
+func (s Static) Idle(ctx context.Context) (time.Duration, error) {
+	return s.Duration, s.Err
+}
+
+type unsupported struct{}
+
+func (unsupported) Idle(ctx context.Context) (time.Duration, error) {
+	return 0, ErrUnsupported
+}
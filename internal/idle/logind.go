@@ -0,0 +1,52 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	logindService = "org.freedesktop.login1"
+	// logindSession resolves to the caller's session, or the user's
+	// graphical session when the caller has none (e.g. a systemd user unit).
+	logindSession   = dbus.ObjectPath("/org/freedesktop/login1/session/auto")
+	logindInterface = "org.freedesktop.login1.Session"
+)
+
+// Logind reads the session's IdleHint and IdleSinceHint over the system bus.
+type Logind struct{}
+
+func (Logind) Idle(ctx context.Context) (time.Duration, error) {
+	conn, err := dbus.ConnectSystemBus(dbus.WithContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("connect to system bus: %w", err)
+	}
+	defer conn.Close()
+
+	obj := conn.Object(logindService, logindSession)
+	var hint bool
+	if err := obj.StoreProperty(logindInterface+".IdleHint", &hint); err != nil {
+		return 0, fmt.Errorf("read IdleHint: %w", err)
+	}
+	var since uint64
+	if err := obj.StoreProperty(logindInterface+".IdleSinceHint", &since); err != nil {
+		return 0, fmt.Errorf("read IdleSinceHint: %w", err)
+	}
+	return idleSince(hint, since, time.Now()), nil
+}
+
+// idleSince converts logind's hint and its microsecond wall clock timestamp
+// into an idle duration.
+func idleSince(hint bool, sinceMicros uint64, now time.Time) time.Duration {
+	if !hint || sinceMicros == 0 {
+		return 0
+	}
+	d := now.Sub(time.UnixMicro(int64(sinceMicros)))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,27 @@
+package idle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIdleSince(t *testing.T) {
+	now := time.Date(2026, 1, 28, 12, 0, 0, 0, time.UTC)
+	since := uint64(now.Add(-7 * time.Minute).UnixMicro())
+
+	tests := []struct {
+		hint  bool
+		since uint64
+		want  time.Duration
+	}{
+		{true, since, 7 * time.Minute},
+		{false, since, 0},
+		{true, 0, 0},
+		{true, uint64(now.Add(time.Minute).UnixMicro()), 0},
+	}
+	for _, tt := range tests {
+		if got := idleSince(tt.hint, tt.since, now); got != tt.want {
+			t.Errorf("idleSince(%v, %d) = %v, want %v", tt.hint, tt.since, got, tt.want)
+		}
+	}
+}
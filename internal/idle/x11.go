@@ -0,0 +1,33 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/screensaver"
+	"github.com/jezek/xgb/xproto"
+)
+
+// X11 asks the MIT-SCREEN-SAVER extension how long ago the last input
+// event arrived.
+type X11 struct{}
+
+func (X11) Idle(ctx context.Context) (time.Duration, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return 0, fmt.Errorf("connect to X server: %w", err)
+	}
+	defer conn.Close()
+
+	if err := screensaver.Init(conn); err != nil {
+		return 0, fmt.Errorf("screensaver extension: %w", err)
+	}
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+	reply, err := screensaver.QueryInfo(conn, xproto.Drawable(root)).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("query screensaver info: %w", err)
+	}
+	return time.Duration(reply.MsSinceUserInput) * time.Millisecond, nil
+}
@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// MaxBytes is the largest encoded image we hand to a classifier.
+	MaxBytes = 3 * 1024 * 1024
+
+	DefaultQuality = 85
+	minQuality     = 40
+	qualityStep    = 10
+	minWidth       = 100
+)
+
+type Options struct {
+	MaxWidth int
+	Format   string // "jpeg" or "png"
+	Quality  int    // JPEG quality 1-100, 0 means DefaultQuality
+	MaxBytes int    // 0 means MaxBytes
+}
+
+type Encoded struct {
+	Data    []byte
+	Width   int
+	Height  int
+	Format  string
+	Quality int
+}
+
+// Process downscales img to opts.MaxWidth and encodes it. If the result is
+// larger than the byte limit, JPEG quality is lowered step by step and, once
+// quality bottoms out (or for PNG), the image is shrunk further.
+func Process(img image.Image, opts Options) (*Encoded, error) {
+	quality := opts.Quality
+	if quality <= 0 {
+		quality = DefaultQuality
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = MaxBytes
+	}
+
+	resized := Resize(img, opts.MaxWidth)
+	for {
+		data, err := encode(resized, opts.Format, quality)
+		if err != nil {
+			return nil, err
+		}
+		b := resized.Bounds()
+		if len(data) <= maxBytes {
+			return &Encoded{Data: data, Width: b.Dx(), Height: b.Dy(), Format: opts.Format, Quality: quality}, nil
+		}
+
+		if opts.Format != "png" && quality-qualityStep >= minQuality {
+			quality -= qualityStep
+			continue
+		}
+		nextWidth := b.Dx() * 3 / 4
+		if nextWidth < minWidth {
+			return nil, fmt.Errorf("image too large after resize: %d bytes at %dx%d", len(data), b.Dx(), b.Dy())
+		}
+		resized = Resize(resized, nextWidth)
+	}
+}
+
+// Resize scales img down so that it is at most maxWidth pixels wide, keeping
+// the aspect ratio. Images that already fit are returned unchanged.
+func Resize(img image.Image, maxWidth int) image.Image {
+	b := img.Bounds()
+	if maxWidth <= 0 || b.Dx() <= maxWidth {
+		return img
+	}
+
+	height := b.Dy() * maxWidth / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, maxWidth, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+func encode(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg", "":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	default:
+		return nil, fmt.Errorf("unsupported image format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"testing"
+)
+
+func noise(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	r := rand.New(rand.NewSource(1))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(r.Intn(256)), uint8(r.Intn(256)), uint8(r.Intn(256)), 0xff})
+		}
+	}
+	return img
+}
+
+func TestResize(t *testing.T) {
+	got := Resize(image.NewRGBA(image.Rect(0, 0, 2000, 1000)), 500)
+	if got.Bounds().Dx() != 500 || got.Bounds().Dy() != 250 {
+		t.Errorf("size = %v, want 500x250", got.Bounds().Size())
+	}
+
+	small := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	if Resize(small, 500) != small {
+		t.Error("image narrower than max width should be returned as is")
+	}
+}
+
+func TestProcessJPEG(t *testing.T) {
+	enc, err := Process(noise(400, 300), Options{MaxWidth: 200, Format: "jpeg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc.Width != 200 || enc.Height != 150 || enc.Quality != DefaultQuality {
+		t.Errorf("got %dx%d q%d", enc.Width, enc.Height, enc.Quality)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(enc.Data)); err != nil {
+		t.Errorf("output is not a jpeg: %v", err)
+	}
+}
+
+func TestProcessEnforcesLimit(t *testing.T) {
+	img := noise(400, 400)
+	for _, format := range []string{"jpeg", "png"} {
+		enc, err := Process(img, Options{MaxWidth: 400, Format: format, MaxBytes: 60 * 1024})
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(enc.Data) > 60*1024 {
+			t.Errorf("%s: %d bytes exceeds limit", format, len(enc.Data))
+		}
+	}
+}
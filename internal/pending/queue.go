@@ -0,0 +1,161 @@
+package pending
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	manifestName = "entry.json"
+	lockName     = ".lock"
+)
+
+// ErrLocked is returned by Lock while another process works on the queue.
+var ErrLocked = errors.New("pending queue is in use by another process")
+
+// Image is a screenshot kept for a later classification attempt.
+type Image struct {
+	Path        string `json:"path"`
+	Description string `json:"description,omitempty"`
+}
+
+// Entry is a failed capture waiting to be classified again. Each entry lives
+// in its own directory named after the event ID, holding copies of the
+// screenshots and a JSON manifest.
+type Entry struct {
+	EventID      string    `json:"eventId"`
+	CapturedAt   time.Time `json:"capturedAt"`
+	Images       []Image   `json:"images"`
+	DisplayCount int       `json:"displayCount"`
+	Resolution   string    `json:"resolution"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"lastError,omitempty"`
+}
+
+type Queue struct {
+	dir string
+}
+
+func Open(dir string) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Queue{dir: dir}, nil
+}
+
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// Add copies the entry's images into the queue and writes its manifest. The
+// entry's image paths are rewritten to point at the copies.
+func (q *Queue) Add(e *Entry) error {
+	entryDir := filepath.Join(q.dir, e.EventID)
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return err
+	}
+
+	images := make([]Image, 0, len(e.Images))
+	for _, img := range e.Images {
+		dst := filepath.Join(entryDir, filepath.Base(img.Path))
+		if err := copyFile(img.Path, dst); err != nil {
+			_ = os.RemoveAll(entryDir)
+			return fmt.Errorf("queue %s: %w", filepath.Base(img.Path), err)
+		}
+		images = append(images, Image{Path: dst, Description: img.Description})
+	}
+	e.Images = images
+
+	if err := q.Update(e); err != nil {
+		_ = os.RemoveAll(entryDir)
+		return err
+	}
+	return nil
+}
+
+// Update rewrites the manifest of an entry already in the queue.
+func (q *Queue) Update(e *Entry) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(q.dir, e.EventID, manifestName), data, 0644)
+}
+
+// List returns queued entries, oldest capture first. Directories without a
+// readable manifest are skipped.
+func (q *Queue) List() ([]*Entry, error) {
+	dirs, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(q.dir, d.Name(), manifestName))
+		if err != nil {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		entries = append(entries, &e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CapturedAt.Before(entries[j].CapturedAt)
+	})
+	return entries, nil
+}
+
+// Lock claims the queue for processing, e.g. by reclassify, so the daemon
+// and the CLI never work on the same entries at once. It fails with
+// ErrLocked instead of waiting. Call the returned func to release the lock.
+func (q *Queue) Lock() (unlock func(), err error) {
+	f, err := os.OpenFile(filepath.Join(q.dir, lockName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	// Closing the file releases the lock.
+	return func() { _ = f.Close() }, nil
+}
+
+// Remove deletes an entry and its images.
+func (q *Queue) Remove(eventID string) error {
+	if eventID == "" {
+		return fmt.Errorf("event id is empty")
+	}
+	return os.RemoveAll(filepath.Join(q.dir, eventID))
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,85 @@
+package pending
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueueRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "screenshot.jpg")
+	if err := os.WriteFile(src, []byte("jpeg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := Open(filepath.Join(t.TempDir(), "pending"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	if err := q.Add(&Entry{EventID: "b", CapturedAt: now, Images: []Image{{Path: src}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(&Entry{EventID: "a", CapturedAt: now.Add(-time.Minute), Images: []Image{{Path: src}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The queue owns copies, so the original can go away.
+	_ = os.Remove(src)
+
+	entries, err := q.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].EventID != "a" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if _, err := os.Stat(entries[0].Images[0].Path); err != nil {
+		t.Errorf("queued image missing: %v", err)
+	}
+
+	entries[0].Attempts = 2
+	if err := q.Update(entries[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ = q.List()
+	if len(entries) != 1 || entries[0].Attempts != 2 {
+		t.Errorf("unexpected entries after update/remove: %+v", entries)
+	}
+}
+
+func TestQueueLock(t *testing.T) {
+	q, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := q.Lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second handle, as another process would use, cannot take it.
+	other, err := Open(q.Dir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Lock(); !errors.Is(err, ErrLocked) {
+		t.Errorf("second Lock = %v, want ErrLocked", err)
+	}
+
+	unlock()
+	unlockOther, err := other.Lock()
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+	unlockOther()
+
+	if entries, err := q.List(); err != nil || len(entries) != 0 {
+		t.Errorf("lock file listed as entry: %v, %v", entries, err)
+	}
+}
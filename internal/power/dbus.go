@@ -0,0 +1,144 @@
+package power
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	signalPrepareForSleep = "org.freedesktop.login1.Manager.PrepareForSleep"
+	// Desktops implement the screensaver interface under either name.
+	signalFreedesktopScreenSaver = "org.freedesktop.ScreenSaver.ActiveChanged"
+	signalGnomeScreenSaver       = "org.gnome.ScreenSaver.ActiveChanged"
+)
+
+// DBus listens for logind's PrepareForSleep on the system bus and the
+// screensaver's ActiveChanged on the session bus. Either bus may be missing;
+// Subscribe only fails when neither is reachable. A bus that drops later is
+// not reconnected; the channel closes once every bus is gone.
+type DBus struct{}
+
+func (DBus) Subscribe(ctx context.Context) (<-chan Signal, error) {
+	var subs []*subscription
+	var errs []error
+
+	if sub, err := watch(dbus.ConnectSystemBus,
+		match("org.freedesktop.login1.Manager", "PrepareForSleep"),
+	); err != nil {
+		errs = append(errs, fmt.Errorf("system bus: %w", err))
+	} else {
+		subs = append(subs, sub)
+	}
+	if sub, err := watch(dbus.ConnectSessionBus,
+		match("org.freedesktop.ScreenSaver", "ActiveChanged"),
+		match("org.gnome.ScreenSaver", "ActiveChanged"),
+	); err != nil {
+		errs = append(errs, fmt.Errorf("session bus: %w", err))
+	} else {
+		subs = append(subs, sub)
+	}
+	if len(subs) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return forward(ctx, subs), nil
+}
+
+// subscription is one bus connection and the channel it delivers signals
+// on. The connection closes the channel when it is closed or drops, so each
+// connection needs a channel of its own.
+type subscription struct {
+	conn    *dbus.Conn
+	signals chan *dbus.Signal
+}
+
+func match(iface, member string) []dbus.MatchOption {
+	return []dbus.MatchOption{dbus.WithMatchInterface(iface), dbus.WithMatchMember(member)}
+}
+
+// watch connects to a bus and subscribes to the matching signals.
+func watch(connect func(...dbus.ConnOption) (*dbus.Conn, error), matches ...[]dbus.MatchOption) (*subscription, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		if err := conn.AddMatchSignal(m...); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	sub := &subscription{conn: conn, signals: make(chan *dbus.Signal, 16)}
+	conn.Signal(sub.signals)
+	return sub, nil
+}
+
+// forward translates the signals of every subscription onto one channel. It
+// closes each connection when ctx is cancelled, and the channel once all of
+// them are done.
+func forward(ctx context.Context, subs []*subscription) <-chan Signal {
+	out := make(chan Signal)
+	var wg sync.WaitGroup
+	for _, sub := range subs {
+		wg.Add(1)
+		go func(sub *subscription) {
+			defer wg.Done()
+			defer sub.conn.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case sig, ok := <-sub.signals:
+					if !ok {
+						return
+					}
+					s, ok := translate(sig, time.Now())
+					if !ok {
+						continue
+					}
+					select {
+					case out <- s:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}(sub)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// translate maps a D-Bus signal onto a Signal. Both signals carry a single
+// boolean: true when going to sleep or when the screensaver (and with it the
+// lock screen) becomes active.
+func translate(sig *dbus.Signal, at time.Time) (Signal, bool) {
+	if sig == nil || len(sig.Body) != 1 {
+		return Signal{}, false
+	}
+	active, ok := sig.Body[0].(bool)
+	if !ok {
+		return Signal{}, false
+	}
+
+	switch sig.Name {
+	case signalPrepareForSleep:
+		if active {
+			return Signal{Kind: Sleep, At: at}, true
+		}
+		return Signal{Kind: Resume, At: at}, true
+	case signalFreedesktopScreenSaver, signalGnomeScreenSaver:
+		if active {
+			return Signal{Kind: Lock, At: at}, true
+		}
+		return Signal{Kind: Unlock, At: at}, true
+	}
+	return Signal{}, false
+}
@@ -0,0 +1,75 @@
+package power
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestTranslate(t *testing.T) {
+	at := time.Date(2026, 1, 28, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		sig  *dbus.Signal
+		want Kind
+		ok   bool
+	}{
+		{&dbus.Signal{Name: signalPrepareForSleep, Body: []interface{}{true}}, Sleep, true},
+		{&dbus.Signal{Name: signalPrepareForSleep, Body: []interface{}{false}}, Resume, true},
+		{&dbus.Signal{Name: signalGnomeScreenSaver, Body: []interface{}{true}}, Lock, true},
+		{&dbus.Signal{Name: signalFreedesktopScreenSaver, Body: []interface{}{false}}, Unlock, true},
+		{&dbus.Signal{Name: signalPrepareForSleep, Body: []interface{}{"yes"}}, "", false},
+		{&dbus.Signal{Name: "org.freedesktop.DBus.NameAcquired", Body: []interface{}{true}}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := translate(tt.sig, at)
+		if ok != tt.ok || got.Kind != tt.want || (ok && !got.At.Equal(at)) {
+			t.Errorf("translate(%s %v) = %+v, %v", tt.sig.Name, tt.sig.Body, got, ok)
+		}
+	}
+}
+
+// pipeSubscription is a subscription on a connection that never talks to a
+// bus, so the test controls its signals and when it closes.
+func pipeSubscription(t *testing.T) *subscription {
+	t.Helper()
+	c, _ := net.Pipe()
+	conn, err := dbus.NewConn(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := &subscription{conn: conn, signals: make(chan *dbus.Signal, 1)}
+	conn.Signal(sub.signals)
+	return sub
+}
+
+func TestForwardClosesConnections(t *testing.T) {
+	system, session := pipeSubscription(t), pipeSubscription(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := forward(ctx, []*subscription{system, session})
+
+	session.signals <- &dbus.Signal{Name: signalGnomeScreenSaver, Body: []interface{}{true}}
+	if s := <-out; s.Kind != Lock {
+		t.Errorf("got %+v, want lock", s)
+	}
+
+	// A dropped bus stops its own forwarding without spinning or taking
+	// the other bus down.
+	_ = system.conn.Close()
+	session.signals <- &dbus.Signal{Name: signalPrepareForSleep, Body: []interface{}{true}}
+	if s := <-out; s.Kind != Sleep {
+		t.Errorf("got %+v, want sleep", s)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("unexpected signal after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
@@ -0,0 +1,54 @@
+// Package power reports sleep, resume, lock and unlock transitions of the
+// desktop session.
+package power
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+)
+
+// ErrUnsupported is returned by sources that cannot watch the session on the
+// current platform.
+var ErrUnsupported = errors.New("lock and sleep signals are not supported here")
+
+type Kind string
+
+const (
+	Sleep  Kind = "sleep"
+	Resume Kind = "resume"
+	Lock   Kind = "lock"
+	Unlock Kind = "unlock"
+)
+
+type Signal struct {
+	Kind Kind
+	At   time.Time
+}
+
+// Source delivers signals until ctx is cancelled, then closes the channel.
+type Source interface {
+	Subscribe(ctx context.Context) (<-chan Signal, error)
+}
+
+// New returns the best Source for the current session.
+func New() Source {
+	if runtime.GOOS == "linux" {
+		return DBus{}
+	}
+	return unsupported{}
+}
+
+// Chan replays signals sent on it. It is meant for tests.
+type Chan chan Signal
+
+func (c Chan) Subscribe(ctx context.Context) (<-chan Signal, error) {
+	return c, nil
+}
+
+type unsupported struct{}
+
+func (unsupported) Subscribe(ctx context.Context) (<-chan Signal, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+// window is a range of minutes since local midnight, end exclusive.
+type window struct {
+	start, end int
+}
+
+type period struct {
+	start, end time.Time
+}
+
+// Calendar decides whether recording is allowed at a given time. Times are
+// compared by local wall clock, so windows keep their meaning across DST
+// changes.
+type Calendar struct {
+	enabled  bool
+	loc      *time.Location
+	windows  map[time.Weekday][]window
+	holidays map[string]bool
+	quiet    []period
+}
+
+func NewCalendar(cfg config.ScheduleConfig, loc *time.Location) (*Calendar, error) {
+	c := &Calendar{
+		enabled:  cfg.Enabled,
+		loc:      loc,
+		windows:  map[time.Weekday][]window{},
+		holidays: map[string]bool{},
+	}
+
+	for day, ranges := range cfg.Windows {
+		wd, err := config.ParseWeekday(day)
+		if err != nil {
+			return nil, fmt.Errorf("schedule.windows: %w", err)
+		}
+		for _, r := range ranges {
+			start, end, err := config.ParseWindow(r)
+			if err != nil {
+				return nil, fmt.Errorf("schedule.windows.%s: %w", day, err)
+			}
+			c.windows[wd] = append(c.windows[wd], window{start, end})
+		}
+	}
+
+	for _, h := range cfg.Holidays {
+		if _, err := time.Parse(config.DateLayout, h); err != nil {
+			return nil, fmt.Errorf("schedule.holidays: %w", err)
+		}
+		c.holidays[h] = true
+	}
+
+	for _, q := range cfg.Quiet {
+		start, end, err := q.Parse(loc)
+		if err != nil {
+			return nil, fmt.Errorf("schedule.quiet: %w", err)
+		}
+		c.quiet = append(c.quiet, period{start, end})
+	}
+	return c, nil
+}
+
+// Allows reports whether t falls inside a working window and outside every
+// holiday and quiet period. A disabled calendar allows everything.
+func (c *Calendar) Allows(t time.Time) bool {
+	if c == nil || !c.enabled {
+		return true
+	}
+	t = t.In(c.loc)
+	if c.holidays[t.Format(config.DateLayout)] {
+		return false
+	}
+	for _, q := range c.quiet {
+		if !t.Before(q.start) && t.Before(q.end) {
+			return false
+		}
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range c.windows[t.Weekday()] {
+		if minute >= w.start && minute < w.end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+func TestCalendarAllows(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("tzdata not available:", err)
+	}
+	cal, err := NewCalendar(config.ScheduleConfig{
+		Enabled: true,
+		Windows: map[string][]string{
+			"mon": {"09:00-12:00", "13:00-18:00"},
+			"sun": {"09:00-24:00"},
+		},
+		Holidays: []string{"2026-04-06"},
+		Quiet:    []config.QuietPeriod{{Start: "2026-03-30 10:00", End: "2026-03-30 11:30"}},
+	}, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 3, 23, 9, 0, 0, 0, loc), true},    // Monday, window start
+		{time.Date(2026, 3, 23, 12, 30, 0, 0, loc), false}, // lunch gap
+		{time.Date(2026, 3, 23, 18, 0, 0, 0, loc), false},  // window end is exclusive
+		{time.Date(2026, 3, 24, 10, 0, 0, 0, loc), false},  // Tuesday has no window
+		{time.Date(2026, 3, 29, 23, 59, 0, 0, loc), true},  // DST change day, wall clock still counts
+		{time.Date(2026, 3, 30, 10, 15, 0, 0, loc), false}, // quiet period
+		{time.Date(2026, 3, 30, 11, 30, 0, 0, loc), true},  // quiet period over
+		{time.Date(2026, 4, 6, 10, 0, 0, 0, loc), false},   // holiday
+		{time.Date(2026, 3, 23, 8, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		if got := cal.Allows(tt.at); got != tt.want {
+			t.Errorf("Allows(%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	if _, err := NewCalendar(config.ScheduleConfig{Windows: map[string][]string{"mon": {"18:00-09:00"}}}, loc); err == nil {
+		t.Error("inverted window should error")
+	}
+}
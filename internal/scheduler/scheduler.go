@@ -2,45 +2,139 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"log"
+	"sync"
 	"time"
+
+	"github.com/aknow2/beholder/internal/power"
 )
 
 type RecordFunc func(ctx context.Context) error
 
+const (
+	SpanSuspended = "SUSPENDED"
+	SpanLocked    = "LOCKED"
+)
+
+// Span is a stretch of time during which capture was paused.
+type Span struct {
+	Kind  string
+	Start time.Time
+	End   time.Time
+}
+
+type SpanFunc func(ctx context.Context, span Span) error
+
+// Clock abstracts time so tests can drive the scheduler deterministically.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
 type Scheduler struct {
-	interval   time.Duration
+	strategy   Strategy
+	clock      Clock
 	recordFunc RecordFunc
 	stopCh     chan struct{}
 	doneCh     chan struct{}
+
+	skip     func(now time.Time) bool
+	signals  <-chan power.Signal
+	spanFunc SpanFunc
+	asleep   bool
+	locked   bool
+	// pause is the span in progress; its End is unset.
+	pause Span
+
+	runCh chan func(ctx context.Context)
+	mu    sync.Mutex
+	next  time.Time
 }
 
+// ErrStopped is returned by Do once the scheduler has stopped.
+var ErrStopped = errors.New("scheduler stopped")
+
 func New(intervalMinutes int, recordFunc RecordFunc) *Scheduler {
+	return NewWithStrategy(Fixed{Interval: time.Duration(intervalMinutes) * time.Minute}, recordFunc)
+}
+
+func NewWithStrategy(strategy Strategy, recordFunc RecordFunc) *Scheduler {
 	return &Scheduler{
-		interval:   time.Duration(intervalMinutes) * time.Minute,
+		strategy:   strategy,
+		clock:      realClock{},
 		recordFunc: recordFunc,
 		stopCh:     make(chan struct{}),
 		doneCh:     make(chan struct{}),
+		runCh:      make(chan func(ctx context.Context)),
 	}
 }
 
+// SkipWhen makes the scheduler skip runs for which skip returns true, e.g.
+// outside working hours. It must be called before Start.
+func (s *Scheduler) SkipWhen(skip func(now time.Time) bool) {
+	s.skip = skip
+}
+
+// SetStrategy replaces the strategy from the next run on; the wait already
+// under way is kept. It must be called before Start or from a function
+// passed to Do.
+func (s *Scheduler) SetStrategy(strategy Strategy) {
+	s.strategy = strategy
+}
+
+// WatchPower pauses recording while the session is asleep or locked and
+// reports each paused stretch to spanFunc once it ends. It must be called
+// before Start.
+func (s *Scheduler) WatchPower(signals <-chan power.Signal, spanFunc SpanFunc) {
+	s.signals = signals
+	s.spanFunc = spanFunc
+}
+
 func (s *Scheduler) Start(ctx context.Context) {
-	ticker := time.NewTicker(s.interval)
-	defer ticker.Stop()
 	defer close(s.doneCh)
 
-	log.Printf("scheduler started with interval: %v", s.interval)
+	log.Printf("scheduler started with %v", s.strategy)
 
+	wait := s.after()
 	for {
 		select {
-		case <-ticker.C:
-			if err := s.recordFunc(ctx); err != nil {
-				log.Printf("scheduled record failed: %v", err)
+		case <-wait:
+			if ctx.Err() != nil {
+				// Cancelled while the timer fired; let the ctx case win.
+				continue
+			}
+			if !s.paused() && (s.skip == nil || !s.skip(s.clock.Now())) {
+				if err := s.recordFunc(ctx); err != nil {
+					log.Printf("scheduled record failed: %v", err)
+				}
+			}
+			wait = s.after()
+		case fn := <-s.runCh:
+			fn(ctx)
+		case sig, ok := <-s.signals:
+			if !ok {
+				s.signals = nil
+				continue
+			}
+			wasPaused := s.paused()
+			s.handleSignal(ctx, sig)
+			if wasPaused && !s.paused() {
+				// Start a fresh interval rather than firing straight away
+				// on wake.
+				wait = s.after()
 			}
 		case <-s.stopCh:
+			s.closeSpan(context.Background(), s.clock.Now())
 			log.Println("scheduler stopped")
 			return
 		case <-ctx.Done():
+			s.closeSpan(context.Background(), s.clock.Now())
 			log.Println("scheduler context cancelled")
 			return
 		}
@@ -51,3 +145,94 @@ func (s *Scheduler) Stop() {
 	close(s.stopCh)
 	<-s.doneCh
 }
+
+// Do runs fn on the scheduler goroutine, between scheduled runs, and waits
+// for it to return.
+func (s *Scheduler) Do(ctx context.Context, fn func(ctx context.Context)) error {
+	done := make(chan struct{})
+	run := func(ctx context.Context) {
+		defer close(done)
+		fn(ctx)
+	}
+	select {
+	case s.runCh <- run:
+	case <-s.doneCh:
+		return ErrStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	<-done
+	return nil
+}
+
+// Next returns when the next run is due.
+func (s *Scheduler) Next() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
+
+// after starts waiting for the next run.
+func (s *Scheduler) after() <-chan time.Time {
+	d := s.strategy.Next()
+	s.mu.Lock()
+	s.next = s.clock.Now().Add(d)
+	s.mu.Unlock()
+	return s.clock.After(d)
+}
+
+func (s *Scheduler) paused() bool {
+	return s.asleep || s.locked
+}
+
+// spanKind is what the current pause should be recorded as. Sleep wins over
+// lock, since a locked laptop that is suspended is not just locked.
+func (s *Scheduler) spanKind() string {
+	switch {
+	case s.asleep:
+		return SpanSuspended
+	case s.locked:
+		return SpanLocked
+	}
+	return ""
+}
+
+func (s *Scheduler) handleSignal(ctx context.Context, sig power.Signal) {
+	before := s.spanKind()
+	switch sig.Kind {
+	case power.Sleep:
+		s.asleep = true
+	case power.Resume:
+		s.asleep = false
+	case power.Lock:
+		s.locked = true
+	case power.Unlock:
+		s.locked = false
+	}
+	after := s.spanKind()
+	if before == after {
+		return
+	}
+
+	log.Printf("session %s", sig.Kind)
+	s.closeSpan(ctx, sig.At)
+	if after != "" {
+		s.pause = Span{Kind: after, Start: sig.At}
+	}
+}
+
+// closeSpan reports the span in progress, if any, as ending at end.
+func (s *Scheduler) closeSpan(ctx context.Context, end time.Time) {
+	if s.pause.Kind == "" {
+		return
+	}
+	span := s.pause
+	span.End = end
+	s.pause = Span{}
+	if s.spanFunc == nil {
+		return
+	}
+	if err := s.spanFunc(ctx, span); err != nil {
+		log.Printf("failed to record %s span: %v", span.Kind, err)
+	}
+}
@@ -2,8 +2,11 @@ package scheduler
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/aknow2/beholder/internal/power"
 )
 
 func TestNew(t *testing.T) {
@@ -15,7 +18,7 @@ func TestNew(t *testing.T) {
 func TestRun(t *testing.T) {
 	c := 0
 	s := New(1, func(ctx context.Context) error { c++; return nil })
-	s.interval = 50 * time.Millisecond
+	s.strategy = Fixed{Interval: 50 * time.Millisecond}
 	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
 	defer cancel()
 	go s.Start(ctx)
@@ -24,3 +27,91 @@ func TestRun(t *testing.T) {
 		t.Error("not called")
 	}
 }
+
+func TestPauseWhileLocked(t *testing.T) {
+	var calls atomic.Int32
+	s := New(1, func(ctx context.Context) error { calls.Add(1); return nil })
+	s.strategy = Fixed{Interval: 20 * time.Millisecond}
+
+	signals := make(chan power.Signal)
+	spans := make(chan Span, 4)
+	s.WatchPower(signals, func(ctx context.Context, span Span) error { spans <- span; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx)
+
+	t0 := time.Date(2026, 1, 28, 12, 0, 0, 0, time.UTC)
+	signals <- power.Signal{Kind: power.Lock, At: t0}
+	before := calls.Load()
+	signals <- power.Signal{Kind: power.Sleep, At: t0.Add(time.Minute)}
+	time.Sleep(100 * time.Millisecond)
+	signals <- power.Signal{Kind: power.Resume, At: t0.Add(time.Hour)}
+	signals <- power.Signal{Kind: power.Unlock, At: t0.Add(time.Hour + 2*time.Minute)}
+	if got := calls.Load(); got != before {
+		t.Errorf("recorded %d times while paused", got-before)
+	}
+
+	want := []Span{
+		{SpanLocked, t0, t0.Add(time.Minute)},
+		{SpanSuspended, t0.Add(time.Minute), t0.Add(time.Hour)},
+		{SpanLocked, t0.Add(time.Hour), t0.Add(time.Hour + 2*time.Minute)},
+	}
+	for _, w := range want {
+		if got := <-spans; got != w {
+			t.Errorf("span = %+v, want %+v", got, w)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if calls.Load() == before {
+		t.Error("recording did not resume after unlock")
+	}
+}
+
+func TestDo(t *testing.T) {
+	s := New(60, func(ctx context.Context) error { return nil })
+	ctx, cancel := context.WithCancel(context.Background())
+	go s.Start(ctx)
+
+	ran := false
+	if err := s.Do(context.Background(), func(ctx context.Context) { ran = true }); err != nil {
+		t.Fatal(err)
+	}
+	if !ran {
+		t.Error("fn did not run")
+	}
+
+	cancel()
+	<-s.doneCh
+	if err := s.Do(context.Background(), func(ctx context.Context) {}); err != ErrStopped {
+		t.Errorf("Do after stop = %v, want ErrStopped", err)
+	}
+}
+
+func TestSetStrategyKeepsPause(t *testing.T) {
+	var calls atomic.Int32
+	s := New(60, func(ctx context.Context) error { calls.Add(1); return nil })
+	signals := make(chan power.Signal)
+	spans := make(chan Span, 1)
+	s.WatchPower(signals, func(ctx context.Context, span Span) error { spans <- span; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx)
+
+	t0 := time.Date(2026, 1, 28, 12, 0, 0, 0, time.UTC)
+	signals <- power.Signal{Kind: power.Lock, At: t0}
+	if err := s.Do(ctx, func(context.Context) { s.SetStrategy(Fixed{Interval: 10 * time.Millisecond}) }); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if calls.Load() != 0 || len(spans) != 0 {
+		t.Errorf("still locked: recorded %d times, %d spans closed", calls.Load(), len(spans))
+	}
+
+	signals <- power.Signal{Kind: power.Unlock, At: t0.Add(time.Hour)}
+	if got := <-spans; got != (Span{SpanLocked, t0, t0.Add(time.Hour)}) {
+		t.Errorf("span = %+v", got)
+	}
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+const (
+	StrategyFixed    = "fixed"
+	StrategyJitter   = "jitter"
+	StrategyAdaptive = "adaptive"
+)
+
+// adaptiveStreak is how many consecutive events must share a category
+// before the adaptive strategy starts backing off.
+const adaptiveStreak = 3
+
+// Strategy decides how long to wait before the next run.
+type Strategy interface {
+	Next() time.Duration
+}
+
+// Observer is implemented by strategies that adapt to what was recorded.
+type Observer interface {
+	Observe(category string)
+}
+
+// NewStrategy returns the Strategy selected by cfg.Strategy. An empty
+// strategy is fixed.
+func NewStrategy(cfg config.SchedulerConfig) (Strategy, error) {
+	interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+	switch cfg.Strategy {
+	case "", StrategyFixed:
+		return Fixed{Interval: interval}, nil
+	case StrategyJitter:
+		return NewJitter(interval, time.Duration(cfg.JitterMinutes)*time.Minute), nil
+	case StrategyAdaptive:
+		return NewAdaptive(interval,
+			time.Duration(cfg.MinIntervalMinutes)*time.Minute,
+			time.Duration(cfg.MaxIntervalMinutes)*time.Minute), nil
+	default:
+		return nil, fmt.Errorf("unknown scheduler strategy: %s", cfg.Strategy)
+	}
+}
+
+// MaxInterval is the longest delay the strategy selected by cfg waits
+// between runs.
+func MaxInterval(cfg config.SchedulerConfig) time.Duration {
+	interval := time.Duration(cfg.IntervalMinutes) * time.Minute
+	switch cfg.Strategy {
+	case StrategyJitter:
+		j := NewJitter(interval, time.Duration(cfg.JitterMinutes)*time.Minute)
+		return j.Interval + j.Window/2
+	case StrategyAdaptive:
+		return NewAdaptive(interval, 0, time.Duration(cfg.MaxIntervalMinutes)*time.Minute).Max
+	default:
+		return interval
+	}
+}
+
+// Fixed runs every Interval.
+type Fixed struct {
+	Interval time.Duration
+}
+
+func (f Fixed) Next() time.Duration {
+	return f.Interval
+}
+
+func (f Fixed) String() string {
+	return fmt.Sprintf("fixed interval %v", f.Interval)
+}
+
+// Jitter runs on average every Interval, each delay drawn uniformly from
+// Interval ± Window/2, so samples do not line up with activities that
+// repeat on the hour.
+type Jitter struct {
+	Interval time.Duration
+	Window   time.Duration
+
+	// rand returns a value in [0, n). It is replaced in tests.
+	rand func(n int64) int64
+}
+
+func NewJitter(interval, window time.Duration) *Jitter {
+	if window > interval {
+		window = interval
+	}
+	return &Jitter{Interval: interval, Window: window, rand: rand.Int63n}
+}
+
+func (j *Jitter) Next() time.Duration {
+	if j.Window <= 0 {
+		return j.Interval
+	}
+	return j.Interval - j.Window/2 + time.Duration(j.rand(int64(j.Window)+1))
+}
+
+func (j *Jitter) String() string {
+	return fmt.Sprintf("interval %v with %v jitter", j.Interval, j.Window)
+}
+
+// Adaptive drops to Min as soon as the category changes and doubles the
+// delay, up to Max, for every event once adaptiveStreak consecutive events
+// have shared a category. Observe must be told each recorded category id.
+type Adaptive struct {
+	Base time.Duration
+	Min  time.Duration
+	Max  time.Duration
+
+	mu       sync.Mutex
+	current  time.Duration
+	category string
+	streak   int
+}
+
+func NewAdaptive(base, min, max time.Duration) *Adaptive {
+	if min <= 0 || min > base {
+		min = base
+	}
+	if max < base {
+		max = base
+	}
+	return &Adaptive{Base: base, Min: min, Max: max, current: base}
+}
+
+func (a *Adaptive) Next() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.current
+}
+
+// Observe records the category id of the event just captured.
+func (a *Adaptive) Observe(category string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.streak == 0 || category != a.category {
+		if a.streak > 0 {
+			a.current = a.Min
+		}
+		a.category = category
+		a.streak = 1
+		return
+	}
+
+	a.streak++
+	if a.streak >= adaptiveStreak {
+		a.current *= 2
+		if a.current > a.Max {
+			a.current = a.Max
+		}
+	}
+}
+
+func (a *Adaptive) String() string {
+	return fmt.Sprintf("adaptive interval %v (%v-%v)", a.Base, a.Min, a.Max)
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+// stepClock hands each requested delay to the test, which fires the timer
+// by sending on the returned channel.
+type stepClock struct {
+	now    time.Time
+	delays chan time.Duration
+	fire   chan time.Time
+}
+
+func newStepClock() *stepClock {
+	return &stepClock{
+		now:    time.Date(2026, 1, 28, 9, 0, 0, 0, time.UTC),
+		delays: make(chan time.Duration),
+		fire:   make(chan time.Time),
+	}
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.delays <- d
+	return c.fire
+}
+
+func TestSchedulerFollowsStrategy(t *testing.T) {
+	draws := []int64{0, 10 * int64(time.Minute), 5 * int64(time.Minute)}
+	j := NewJitter(10*time.Minute, 10*time.Minute)
+	j.rand = func(n int64) int64 {
+		v := draws[0]
+		draws = draws[1:]
+		return v
+	}
+
+	recorded := make(chan struct{})
+	s := NewWithStrategy(j, func(ctx context.Context) error { recorded <- struct{}{}; return nil })
+	clock := newStepClock()
+	s.clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Start(ctx)
+
+	for i, want := range []time.Duration{5 * time.Minute, 15 * time.Minute, 10 * time.Minute} {
+		if i > 0 {
+			clock.fire <- clock.now
+			<-recorded
+		}
+		if got := <-clock.delays; got != want {
+			t.Errorf("delay %d = %v, want %v", i, got, want)
+		}
+		if next := s.Next(); !next.Equal(clock.now.Add(want)) {
+			t.Errorf("Next() = %v, want %v", next, clock.now.Add(want))
+		}
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	a := NewAdaptive(10*time.Minute, 2*time.Minute, 30*time.Minute)
+
+	steps := []struct {
+		category string
+		want     time.Duration
+	}{
+		{"implement", 10 * time.Minute},
+		{"implement", 10 * time.Minute},
+		{"implement", 20 * time.Minute},
+		{"implement", 30 * time.Minute},
+		{"meeting", 2 * time.Minute},
+		{"meeting", 2 * time.Minute},
+		{"meeting", 4 * time.Minute},
+	}
+	for i, step := range steps {
+		a.Observe(step.category)
+		if got := a.Next(); got != step.want {
+			t.Errorf("step %d (%s): Next() = %v, want %v", i, step.category, got, step.want)
+		}
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	if _, err := NewStrategy(config.SchedulerConfig{IntervalMinutes: 10, Strategy: "poisson"}); err == nil {
+		t.Error("unknown strategy should error")
+	}
+	s, err := NewStrategy(config.SchedulerConfig{IntervalMinutes: 10, Strategy: StrategyJitter, JitterMinutes: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := s.(*Jitter); j.Window != 10*time.Minute {
+		t.Errorf("jitter window should be capped at the interval, got %v", j.Window)
+	}
+}
+
+func TestMaxInterval(t *testing.T) {
+	tests := []struct {
+		cfg  config.SchedulerConfig
+		want time.Duration
+	}{
+		{config.SchedulerConfig{IntervalMinutes: 10}, 10 * time.Minute},
+		{config.SchedulerConfig{IntervalMinutes: 10, Strategy: StrategyJitter, JitterMinutes: 4}, 12 * time.Minute},
+		{config.SchedulerConfig{IntervalMinutes: 10, Strategy: StrategyAdaptive, MinIntervalMinutes: 2, MaxIntervalMinutes: 30}, 30 * time.Minute},
+		{config.SchedulerConfig{IntervalMinutes: 10, Strategy: StrategyAdaptive}, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := MaxInterval(tt.cfg); got != tt.want {
+			t.Errorf("MaxInterval(%+v) = %v, want %v", tt.cfg, got, tt.want)
+		}
+	}
+}
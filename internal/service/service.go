@@ -0,0 +1,181 @@
+// Package service installs beholder as a per-user service that starts the
+// daemon on login: a systemd user unit on Linux, a launchd agent on macOS.
+package service
+
+import (
+	"bytes"
+	"embed"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	systemdUnitName = "beholder.service"
+	launchdLabel    = "com.aknow2.beholder"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"quote": systemdQuote,
+	"xml":   xmlEscape,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Spec is what the rendered service runs. All paths must be absolute.
+type Spec struct {
+	Binary     string
+	ConfigPath string
+	LogFile    string
+}
+
+// Runner runs a service manager command. Tests replace it so nothing
+// reaches the real systemctl or launchctl.
+type Runner func(name string, args ...string) error
+
+func execRunner(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+type Manager interface {
+	Name() string
+	// Path is where the unit or agent file is installed.
+	Path() string
+	Render(spec Spec) ([]byte, error)
+	Install(spec Spec) error
+	Uninstall() error
+}
+
+// New returns the Manager for goos, installing under homeDir.
+func New(goos, homeDir string) (Manager, error) {
+	switch goos {
+	case "linux":
+		return &Systemd{Dir: filepath.Join(homeDir, ".config", "systemd", "user"), Run: execRunner}, nil
+	case "darwin":
+		return &Launchd{Dir: filepath.Join(homeDir, "Library", "LaunchAgents"), Run: execRunner}, nil
+	default:
+		return nil, fmt.Errorf("service install is not supported on %s", goos)
+	}
+}
+
+// Systemd manages a systemd --user unit.
+type Systemd struct {
+	Dir string
+	Run Runner
+}
+
+func (s *Systemd) Name() string { return "systemd" }
+
+func (s *Systemd) Path() string { return filepath.Join(s.Dir, systemdUnitName) }
+
+func (s *Systemd) Render(spec Spec) ([]byte, error) {
+	return render("beholder.service.tmpl", spec)
+}
+
+func (s *Systemd) Install(spec Spec) error {
+	data, err := s.Render(spec)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(s.Path(), data); err != nil {
+		return err
+	}
+	if err := s.Run("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	return s.Run("systemctl", "--user", "enable", "--now", systemdUnitName)
+}
+
+func (s *Systemd) Uninstall() error {
+	if _, err := os.Stat(s.Path()); os.IsNotExist(err) {
+		return nil
+	}
+	if err := s.Run("systemctl", "--user", "disable", "--now", systemdUnitName); err != nil {
+		return err
+	}
+	if err := os.Remove(s.Path()); err != nil {
+		return err
+	}
+	return s.Run("systemctl", "--user", "daemon-reload")
+}
+
+// Launchd manages a launchd user agent.
+type Launchd struct {
+	Dir string
+	Run Runner
+}
+
+func (l *Launchd) Name() string { return "launchd" }
+
+func (l *Launchd) Path() string { return filepath.Join(l.Dir, launchdLabel+".plist") }
+
+func (l *Launchd) Render(spec Spec) ([]byte, error) {
+	return render("launchd.plist.tmpl", struct {
+		Spec
+		Label string
+	}{spec, launchdLabel})
+}
+
+func (l *Launchd) Install(spec Spec) error {
+	data, err := l.Render(spec)
+	if err != nil {
+		return err
+	}
+	// Unload first so reinstalling picks up the new plist.
+	if _, err := os.Stat(l.Path()); err == nil {
+		_ = l.Run("launchctl", "unload", l.Path())
+	}
+	if err := writeFile(l.Path(), data); err != nil {
+		return err
+	}
+	return l.Run("launchctl", "load", "-w", l.Path())
+}
+
+func (l *Launchd) Uninstall() error {
+	if _, err := os.Stat(l.Path()); os.IsNotExist(err) {
+		return nil
+	}
+	if err := l.Run("launchctl", "unload", "-w", l.Path()); err != nil {
+		return err
+	}
+	return os.Remove(l.Path())
+}
+
+func render(name string, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("render %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// systemdQuote quotes an ExecStart argument. Inside double quotes systemd
+// unescapes backslashes and quotes, and a literal % must be doubled.
+func systemdQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `%`, `%%`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func xmlEscape(s string) (string, error) {
+	var buf bytes.Buffer
+	if err := xml.EscapeText(&buf, []byte(s)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aknow2/beholder/internal/testutil"
+)
+
+var testSpec = Spec{
+	Binary:     "/home/alice/.local/bin/beholder",
+	ConfigPath: "/home/alice/My Config/beholder & co.yaml",
+	LogFile:    "/home/alice/.beholder/daemon.log",
+}
+
+func TestRenderGolden(t *testing.T) {
+	for _, goos := range []string{"linux", "darwin"} {
+		m, err := New(goos, "/home/alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := m.Render(testSpec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.CheckGolden(t, filepath.Base(m.Path())+".golden", data)
+	}
+}
+
+func TestSystemdInstall(t *testing.T) {
+	var calls []string
+	s := &Systemd{Dir: t.TempDir(), Run: func(name string, args ...string) error {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return nil
+	}}
+
+	if err := s.Install(testSpec); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Path()); err != nil {
+		t.Errorf("unit not written: %v", err)
+	}
+	if err := s.Uninstall(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Path()); !os.IsNotExist(err) {
+		t.Errorf("unit not removed: %v", err)
+	}
+
+	want := []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable --now beholder.service",
+		"systemctl --user disable --now beholder.service",
+		"systemctl --user daemon-reload",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("commands = %q, want %q", calls, want)
+	}
+}
+
+func TestNewUnsupported(t *testing.T) {
+	if _, err := New("windows", `C:\Users\alice`); err == nil {
+		t.Error("windows should be unsupported")
+	}
+}
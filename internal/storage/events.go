@@ -3,87 +3,183 @@ package storage
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
 func dateRangeUTC(date time.Time) (time.Time, time.Time) {
-	loc := date.Location()
+	start, end := DateRange(date, date)
+	return start.UTC(), end.UTC()
+}
+
+// DateRange returns local midnight at the start of from and at the end of
+// to, both in from's location. Days are counted on the calendar rather than
+// as 24 hours so that days with a DST transition are 23 or 25 hours long.
+func DateRange(from, to time.Time) (time.Time, time.Time) {
+	loc := from.Location()
 	if loc == nil {
 		loc = time.Local
 	}
-	startLocal := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
-	endLocal := startLocal.Add(24 * time.Hour)
-	return startLocal.UTC(), endLocal.UTC()
+	to = to.In(loc)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc)
+	return start, end
 }
 
+// InsertEvent stores event. Its detected apps and keywords go to the
+// event_apps and event_keywords tables only; the JSON columns on events are
+// left over from before them and are no longer written.
 func (s *Store) InsertEvent(event *Event) error {
-	appsJSON, _ := json.Marshal(event.DetectedApps)
-	keywordsJSON, _ := json.Marshal(event.DetectedKeywords)
+	displaysJSON, _ := json.Marshal(event.Displays)
 
-	_, err := s.DB.Exec(`INSERT INTO events (
-		id, captured_at, category_name, confidence, status, agent_version, screenshot_hash, detected_apps, detected_keywords, notes, created_at
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		event.ID,
-		event.CapturedAt.UTC().Format(time.RFC3339),
-		event.CategoryName,
-		event.Confidence,
-		event.Status,
-		event.AgentVersion,
-		event.ScreenshotHash,
-		string(appsJSON),
-		string(keywordsJSON),
-		event.Notes,
-		event.CreatedAt.UTC().Format(time.RFC3339),
-	)
-	return err
+	return withTx(s.DB, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO events (
+			id, captured_at, category_id, category_name, confidence, status, agent_version, screenshot_hash,
+			classified_by, classifier_provider, rationale, error_kind, error_message, classify_attempts, display_count, display_layout, resolution,
+			span_end, idle_seconds, notes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			event.ID,
+			event.CapturedAt.UTC().Format(time.RFC3339),
+			nullString(event.CategoryID),
+			event.CategoryName,
+			event.Confidence,
+			event.Status,
+			event.AgentVersion,
+			event.ScreenshotHash,
+			event.ClassifiedBy,
+			nullString(event.ClassifierProvider),
+			event.Rationale,
+			event.ErrorKind,
+			event.ErrorMessage,
+			event.ClassifyAttempts,
+			event.DisplayCount,
+			string(displaysJSON),
+			event.Resolution,
+			nullTime(event.SpanEnd),
+			sql.NullInt64{Int64: int64(event.IdleSeconds), Valid: event.IdleSeconds > 0},
+			event.Notes,
+			event.CreatedAt.UTC().Format(time.RFC3339),
+		)
+		if err != nil {
+			return err
+		}
+		return insertDetected(tx, event)
+	})
 }
 
-func (s *Store) ListEventsByDate(date time.Time) ([]Event, error) {
-	start, end := dateRangeUTC(date)
+// UpdateClassification overwrites the classification fields of an existing
+// event, leaving capture details untouched. The detected apps and keywords
+// are replaced in their tables and any legacy JSON copy is cleared.
+func (s *Store) UpdateClassification(event *Event) error {
+	return withTx(s.DB, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`UPDATE events SET
+			category_id = ?, category_name = ?, confidence = ?, status = ?, agent_version = ?, detected_apps = NULL, detected_keywords = NULL,
+			classified_by = ?, classifier_provider = ?, rationale = ?, error_kind = ?, error_message = ?, classify_attempts = ?, notes = ?
+		WHERE id = ?`,
+			nullString(event.CategoryID),
+			event.CategoryName,
+			event.Confidence,
+			event.Status,
+			event.AgentVersion,
+			event.ClassifiedBy,
+			nullString(event.ClassifierProvider),
+			event.Rationale,
+			event.ErrorKind,
+			event.ErrorMessage,
+			event.ClassifyAttempts,
+			event.Notes,
+			event.ID,
+		)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return sql.ErrNoRows
+		}
+		if err := deleteDetected(tx, `event_id = ?`, event.ID); err != nil {
+			return err
+		}
+		return insertDetected(tx, event)
+	})
+}
 
-	rows, err := s.DB.Query(`SELECT id, captured_at, category_name, confidence, status, agent_version, screenshot_hash, detected_apps, detected_keywords, notes, created_at
-		FROM events WHERE captured_at >= ? AND captured_at < ? ORDER BY captured_at ASC`,
-		start.Format(time.RFC3339), end.Format(time.RFC3339))
-	if err != nil {
-		return nil, err
+// insertDetected writes the detected apps and keywords of event to their
+// child tables.
+func insertDetected(tx *sql.Tx, event *Event) error {
+	for i, name := range event.DetectedApps {
+		if _, err := tx.Exec(`INSERT INTO event_apps (event_id, position, name) VALUES (?, ?, ?)`, event.ID, i, name); err != nil {
+			return err
+		}
 	}
-	defer rows.Close()
-
-	var results []Event
-	for rows.Next() {
-		var e Event
-		var capturedAt string
-		var createdAt string
-		var detectedApps string
-		var detectedKeywords string
-		if err := rows.Scan(&e.ID, &capturedAt, &e.CategoryName, &e.Confidence, &e.Status, &e.AgentVersion, &e.ScreenshotHash, &detectedApps, &detectedKeywords, &e.Notes, &createdAt); err != nil {
-			return nil, err
+	for i, keyword := range event.DetectedKeywords {
+		if _, err := tx.Exec(`INSERT INTO event_keywords (event_id, position, keyword) VALUES (?, ?, ?)`, event.ID, i, keyword); err != nil {
+			return err
 		}
-		e.CapturedAt, _ = time.Parse(time.RFC3339, capturedAt)
-		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
-		_ = json.Unmarshal([]byte(detectedApps), &e.DetectedApps)
-		_ = json.Unmarshal([]byte(detectedKeywords), &e.DetectedKeywords)
-		results = append(results, e)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return nil
+}
+
+// deleteDetected removes the child rows of the events matched by where, an
+// expression over the events table.
+func deleteDetected(tx *sql.Tx, where string, args ...any) error {
+	for _, table := range []string{"event_apps", "event_keywords"} {
+		q := fmt.Sprintf(`DELETE FROM %s WHERE event_id IN (SELECT id FROM events WHERE %s)`, table, where)
+		if _, err := tx.Exec(q, args...); err != nil {
+			return err
+		}
 	}
-	return results, nil
+	return nil
+}
+
+func (s *Store) ListEventsByDate(date time.Time) ([]Event, error) {
+	start, end := dateRangeUTC(date)
+	return s.ListEvents(EventQuery{From: start, To: end})
 }
 
 func (s *Store) DeleteEventsByDate(date time.Time) (int64, error) {
 	start, end := dateRangeUTC(date)
+	from, to := start.Format(time.RFC3339), end.Format(time.RFC3339)
 
-	res, err := s.DB.Exec(`DELETE FROM events WHERE captured_at >= ? AND captured_at < ?`,
-		start.Format(time.RFC3339), end.Format(time.RFC3339))
+	var deleted int64
+	err := withTx(s.DB, func(tx *sql.Tx) error {
+		if err := deleteDetected(tx, `captured_at >= ? AND captured_at < ?`, from, to); err != nil {
+			return err
+		}
+		res, err := tx.Exec(`DELETE FROM events WHERE captured_at >= ? AND captured_at < ?`, from, to)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return 0, err
+	return deleted, nil
+}
+
+// backfillCategoryIDs sets category_id on events recorded before it was
+// stored, matching category_name against ids, a map from name to id.
+func backfillCategoryIDs(tx *sql.Tx, ids map[string]string) error {
+	for name, id := range ids {
+		if _, err := tx.Exec(`UPDATE events SET category_id = ? WHERE category_id IS NULL AND category_name = ?`, id, name); err != nil {
+			return err
+		}
 	}
-	return rows, nil
+	return nil
 }
 
-var _ = sql.ErrNoRows
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(time.RFC3339), Valid: true}
+}
@@ -1,32 +1,199 @@
 package storage
 
-import "database/sql"
-
-func (s *Store) Migrate() error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS events (
-			id TEXT PRIMARY KEY,
-			captured_at TEXT NOT NULL,
-			category_name TEXT,
-			confidence REAL,
-			status TEXT NOT NULL,
-			agent_version TEXT,
-			screenshot_hash TEXT,
-			detected_apps TEXT,
-			detected_keywords TEXT,
-			notes TEXT,
-			created_at TEXT NOT NULL
-		);`,
-	}
-
-	for _, q := range queries {
-		if _, err := s.DB.Exec(q); err != nil {
-			return err
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Migration is one numbered schema change from migrations/NNNN_name.sql.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationState is a migration and whether it has been applied.
+type MigrationState struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// baselineVersion is the migration matching the events table created by
+// releases from before schema_migrations existed.
+const baselineVersion = 1
+
+// Migrations returns every embedded migration in version order.
+func Migrations() ([]Migration, error) {
+	entries, err := migrationFS.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, e := range entries {
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(e.Name(), ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %s: name must be NNNN_description.sql", e.Name())
+		}
+		data, err := migrationFS.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(data)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %04d_%s: versions must be numbered 1, 2, 3, ... without gaps", m.Version, m.Name)
+		}
+	}
+	return migrations, nil
+}
+
+// categoryIDVersion is the migration that added events.category_id. Events
+// from before it are matched to the configured categories by name when it
+// is applied, and only then, so it happens once per database.
+const categoryIDVersion = 3
+
+// Migrate applies every pending migration, each in its own transaction.
+// categoryIDs maps the configured category names to their ids and is used
+// to fill in category_id for events recorded before it existed; events
+// whose category has since been renamed keep an empty id.
+func (s *Store) Migrate(categoryIDs map[string]string) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	if err := s.ensureMigrationsTable(); err != nil {
+		return err
+	}
+	if err := s.adoptLegacySchema(); err != nil {
+		return err
+	}
+
+	applied, err := s.appliedMigrations()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := withTx(s.DB, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(m.SQL); err != nil {
+				return err
+			}
+			if m.Version == categoryIDVersion {
+				if err := backfillCategoryIDs(tx, categoryIDs); err != nil {
+					return err
+				}
+			}
+			return recordMigration(tx, m.Version, m.Name)
+		})
+		if err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
 		}
 	}
 	return nil
 }
 
+// MigrationStatus lists every migration and whether it has been applied,
+// without changing the database.
+func (s *Store) MigrationStatus() ([]MigrationState, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int]time.Time{}
+	var exists int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists > 0 {
+		if applied, err = s.appliedMigrations(); err != nil {
+			return nil, err
+		}
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, m := range migrations {
+		at, ok := applied[m.Version]
+		states = append(states, MigrationState{Migration: m, Applied: ok, AppliedAt: at})
+	}
+	return states, nil
+}
+
+func (s *Store) ensureMigrationsTable() error {
+	_, err := s.DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT NOT NULL
+	)`)
+	return err
+}
+
+func (s *Store) appliedMigrations() (map[int]time.Time, error) {
+	rows, err := s.DB.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at string
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version], _ = time.Parse(time.RFC3339, at)
+	}
+	return applied, rows.Err()
+}
+
+// adoptLegacySchema records the baseline migration for a database created
+// before schema_migrations existed. Such a database has an events table but
+// no recorded versions, and the later migrations apply to it as usual.
+func (s *Store) adoptLegacySchema() error {
+	var recorded, events int
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&recorded); err != nil {
+		return err
+	}
+	if err := s.DB.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'events'`).Scan(&events); err != nil {
+		return err
+	}
+	if recorded > 0 || events == 0 {
+		return nil
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	return withTx(s.DB, func(tx *sql.Tx) error {
+		m := migrations[baselineVersion-1]
+		return recordMigration(tx, m.Version, m.Name)
+	})
+}
+
+func recordMigration(tx *sql.Tx, version int, name string) error {
+	_, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		version, name, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
 func withTx(db *sql.DB, fn func(*sql.Tx) error) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openFixture(t *testing.T, fixture string) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "fixture.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if fixture == "" {
+		return s
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DB.Exec(string(data)); err != nil {
+		t.Fatalf("load %s: %v", fixture, err)
+	}
+	return s
+}
+
+func tableColumns(t *testing.T, s *Store, table string) map[string]string {
+	t.Helper()
+	rows, err := s.DB.Query(`SELECT name, type, "notnull", COALESCE(dflt_value, '') FROM pragma_table_info(?)`, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	cols := map[string]string{}
+	for rows.Next() {
+		var name, typ, def string
+		var notNull int
+		if err := rows.Scan(&name, &typ, &notNull, &def); err != nil {
+			t.Fatal(err)
+		}
+		cols[name] = fmt.Sprintf("%s notnull=%d default=%s", typ, notNull, def)
+	}
+	return cols
+}
+
+func TestMigrateUpgradesFixtures(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrations[len(migrations)-1].Version
+
+	fresh := openFixture(t, "")
+	if err := fresh.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+	want := tableColumns(t, fresh, "events")
+
+	fixtures := []string{"legacy_initial.sql"}
+	for v := 1; v < latest; v++ {
+		fixtures = append(fixtures, fmt.Sprintf("v%d.sql", v))
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture, func(t *testing.T) {
+			s := openFixture(t, fixture)
+			if err := s.Migrate(nil); err != nil {
+				t.Fatal(err)
+			}
+			// Running again must be a no-op.
+			if err := s.Migrate(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			states, err := s.MigrationStatus()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, st := range states {
+				if !st.Applied {
+					t.Errorf("migration %d not applied", st.Version)
+				}
+			}
+			if got := tableColumns(t, s, "events"); !reflect.DeepEqual(got, want) {
+				t.Errorf("events columns differ from a fresh database:\ngot  %v\nwant %v", got, want)
+			}
+
+			events, err := s.ListEventsByDate(time.Date(2026, 1, 28, 1, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 1 || events[0].ID != "e1" || events[0].CategoryName != "実装" {
+				t.Fatalf("fixture event not preserved: %+v", events)
+			}
+			if e := events[0]; e.Rationale != "editing" || !strings.HasSuffix(e.Resolution, "x1080") || e.Notes != "" {
+				t.Errorf("notes not split into columns: %+v", e)
+			}
+			if got := detected(t, s, "e1"); !reflect.DeepEqual(got, []string{"app:code", "keyword:beholder"}) {
+				t.Errorf("detected = %v", got)
+			}
+		})
+	}
+}
+
+// detected lists the child rows of an event as "app:name" and
+// "keyword:word".
+func detected(t *testing.T, s *Store, id string) []string {
+	t.Helper()
+	rows, err := s.DB.Query(`SELECT 'app:' || name FROM event_apps WHERE event_id = ?
+		UNION ALL SELECT 'keyword:' || keyword FROM event_keywords WHERE event_id = ?`, id, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+	return got
+}
+
+func TestNormalizeSkipsMalformedRows(t *testing.T) {
+	s := openFixture(t, "v2.sql")
+	if _, err := s.DB.Exec(`INSERT INTO events (id, captured_at, status, detected_apps, detected_keywords, notes, created_at) VALUES
+		('bad', '2026-01-28T02:00:00Z', 'FAILED', 'not json', '"scalar"', 'free text', '2026-01-28T02:00:00Z'),
+		('nil', '2026-01-28T03:00:00Z', 'FAILED', 'null', NULL, NULL, '2026-01-28T03:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := detected(t, s, "bad"); got != nil {
+		t.Errorf("detected = %v", got)
+	}
+
+	var notes string
+	var rationale sql.NullString
+	if err := s.DB.QueryRow(`SELECT notes, rationale FROM events WHERE id = 'bad'`).Scan(&notes, &rationale); err != nil {
+		t.Fatal(err)
+	}
+	if notes != "free text" || rationale.Valid {
+		t.Errorf("unrecognised notes changed: notes=%q rationale=%v", notes, rationale)
+	}
+}
+
+func TestMigrateMovesSpanNotes(t *testing.T) {
+	s := openFixture(t, "v3.sql")
+	if _, err := s.DB.Exec(`INSERT INTO events (id, captured_at, category_id, category_name, confidence, status, agent_version, screenshot_hash, notes, created_at) VALUES
+		('locked', '2026-01-28T02:00:00Z', 'afk', '離席', 1, 'LOCKED', '', '', 'spanEnd=2026-01-28T02:40:00Z durationSeconds=2400', '2026-01-28T02:00:00Z'),
+		('idle', '2026-01-28T03:00:00Z', 'afk', '離席', 1, 'IDLE', '', '', 'idleSeconds=420', '2026-01-28T03:00:00Z')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := s.ListEvents(EventQuery{Statuses: []string{"LOCKED", "IDLE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %+v", events)
+	}
+	locked, idle := events[0], events[1]
+	if want := time.Date(2026, 1, 28, 2, 40, 0, 0, time.UTC); !locked.SpanEnd.Equal(want) || locked.Notes != "" {
+		t.Errorf("locked: span end %v, notes %q", locked.SpanEnd, locked.Notes)
+	}
+	if idle.IdleSeconds != 420 || !idle.SpanEnd.IsZero() || idle.Notes != "" {
+		t.Errorf("idle: %d seconds, span end %v, notes %q", idle.IdleSeconds, idle.SpanEnd, idle.Notes)
+	}
+}
+
+func TestMigrationStatusPending(t *testing.T) {
+	s := openFixture(t, "v1.sql")
+	states, err := s.MigrationStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !states[0].Applied || states[1].Applied {
+		t.Errorf("want only version 1 applied: %+v", states)
+	}
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS events (
+	id TEXT PRIMARY KEY,
+	captured_at TEXT NOT NULL,
+	category_name TEXT,
+	confidence REAL,
+	status TEXT NOT NULL,
+	agent_version TEXT,
+	screenshot_hash TEXT,
+	detected_apps TEXT,
+	detected_keywords TEXT,
+	notes TEXT,
+	created_at TEXT NOT NULL
+);
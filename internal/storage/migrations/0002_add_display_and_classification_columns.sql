@@ -0,0 +1,6 @@
+ALTER TABLE events ADD COLUMN display_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE events ADD COLUMN display_layout TEXT;
+ALTER TABLE events ADD COLUMN classified_by TEXT;
+ALTER TABLE events ADD COLUMN error_kind TEXT;
+ALTER TABLE events ADD COLUMN error_message TEXT;
+ALTER TABLE events ADD COLUMN classify_attempts INTEGER NOT NULL DEFAULT 1;
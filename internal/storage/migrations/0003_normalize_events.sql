@@ -0,0 +1,43 @@
+ALTER TABLE events ADD COLUMN category_id TEXT;
+ALTER TABLE events ADD COLUMN rationale TEXT;
+ALTER TABLE events ADD COLUMN resolution TEXT;
+ALTER TABLE events ADD COLUMN classifier_provider TEXT;
+
+CREATE INDEX idx_events_captured_at ON events (captured_at);
+
+-- One row per detected app or keyword so events can be filtered by them.
+-- These tables are the only copy from here on; the JSON columns on events
+-- are copied below and then left to legacy rows.
+CREATE TABLE event_apps (
+	event_id TEXT NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	PRIMARY KEY (event_id, position)
+);
+CREATE INDEX idx_event_apps_name ON event_apps (name);
+
+CREATE TABLE event_keywords (
+	event_id TEXT NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	keyword TEXT NOT NULL,
+	PRIMARY KEY (event_id, position)
+);
+CREATE INDEX idx_event_keywords_keyword ON event_keywords (keyword);
+
+-- Earlier releases packed these into notes as
+-- "rationale=<text> displayCount=<n> resolution=<WxH>".
+UPDATE events SET
+	rationale = substr(notes, 11, instr(notes, ' displayCount=') - 11),
+	resolution = substr(notes, instr(notes, ' resolution=') + 12),
+	notes = ''
+WHERE notes LIKE 'rationale=% displayCount=% resolution=%';
+
+INSERT INTO event_apps (event_id, position, name)
+SELECT e.id, j.key, j.value
+FROM events e, json_each(CASE WHEN json_valid(e.detected_apps) AND json_type(e.detected_apps) = 'array' THEN e.detected_apps END) j
+WHERE j.type = 'text';
+
+INSERT INTO event_keywords (event_id, position, keyword)
+SELECT e.id, j.key, j.value
+FROM events e, json_each(CASE WHEN json_valid(e.detected_keywords) AND json_type(e.detected_keywords) = 'array' THEN e.detected_keywords END) j
+WHERE j.type = 'text';
@@ -0,0 +1,15 @@
+ALTER TABLE events ADD COLUMN span_end TEXT;
+ALTER TABLE events ADD COLUMN idle_seconds INTEGER;
+
+-- LOCKED and SUSPENDED events kept their end in notes as
+-- "spanEnd=<RFC 3339> durationSeconds=<n>"; the duration follows from it.
+UPDATE events SET
+	span_end = substr(notes, 9, instr(notes, ' durationSeconds=') - 9),
+	notes = ''
+WHERE notes LIKE 'spanEnd=% durationSeconds=%';
+
+-- IDLE events kept their idle time as "idleSeconds=<n>".
+UPDATE events SET
+	idle_seconds = CAST(substr(notes, 13) AS INTEGER),
+	notes = ''
+WHERE notes LIKE 'idleSeconds=%';
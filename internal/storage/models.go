@@ -13,15 +13,39 @@ type Category struct {
 }
 
 type Event struct {
-	ID               string
-	CapturedAt       time.Time
-	CategoryName     string
-	Confidence       float64
-	Status           string
-	AgentVersion     string
-	ScreenshotHash   string
-	DetectedApps     []string
-	DetectedKeywords []string
-	Notes            string
-	CreatedAt        time.Time
+	ID                 string
+	CapturedAt         time.Time
+	CategoryID         string
+	CategoryName       string
+	Confidence         float64
+	Status             string
+	AgentVersion       string
+	ScreenshotHash     string
+	DetectedApps       []string
+	DetectedKeywords   []string
+	ClassifiedBy       string // "rule" or "llm"; empty when classification failed
+	ClassifierProvider string // provider configured at the time, e.g. "copilot"
+	Rationale          string
+	ErrorKind          string // why classification failed, e.g. "timeout" or "auth"
+	ErrorMessage       string
+	ClassifyAttempts   int
+	DisplayCount       int
+	Displays           []DisplayInfo
+	Resolution         string
+	SpanEnd            time.Time // end of a LOCKED or SUSPENDED span; zero otherwise
+	IdleSeconds        int       // how long input had been idle, for IDLE events
+	Notes              string
+	CreatedAt          time.Time
+}
+
+// DisplayInfo describes one monitor at capture time. X and Y are its
+// position in the virtual desktop; Width and Height are its native pixels.
+type DisplayInfo struct {
+	Index   int  `json:"index"`
+	X       int  `json:"x"`
+	Y       int  `json:"y"`
+	Width   int  `json:"width"`
+	Height  int  `json:"height"`
+	Primary bool `json:"primary,omitempty"`
+	Focused bool `json:"focused,omitempty"`
 }
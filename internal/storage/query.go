@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// EventQuery selects events for ListEvents. Zero fields do not filter.
+type EventQuery struct {
+	From time.Time // inclusive
+	To   time.Time // exclusive
+
+	// SpanEndAfter keeps only LOCKED and SUSPENDED events whose recorded
+	// span ends after it.
+	SpanEndAfter time.Time
+
+	// Categories matches either the category id or its name.
+	Categories    []string
+	Statuses      []string
+	MinConfidence float64
+	MaxConfidence float64 // 0 means no upper bound
+	App           string  // a detected app, compared case-insensitively
+	Keyword       string  // a detected keyword, compared case-insensitively
+
+	Limit  int
+	Offset int
+	Order  string // OrderAsc (default) or OrderDesc by captured_at
+}
+
+// where builds the WHERE clause and its arguments for q.
+func (q EventQuery) where() (string, []any, error) {
+	var conds []string
+	var args []any
+
+	if !q.From.IsZero() {
+		conds = append(conds, `captured_at >= ?`)
+		args = append(args, q.From.UTC().Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		conds = append(conds, `captured_at < ?`)
+		args = append(args, q.To.UTC().Format(time.RFC3339))
+	}
+	if !q.SpanEndAfter.IsZero() {
+		conds = append(conds, `span_end > ?`)
+		args = append(args, q.SpanEndAfter.UTC().Format(time.RFC3339))
+	}
+	if len(q.Categories) > 0 {
+		in := placeholders(len(q.Categories))
+		conds = append(conds, fmt.Sprintf(`(category_id IN (%s) OR category_name IN (%s))`, in, in))
+		for range 2 {
+			for _, c := range q.Categories {
+				args = append(args, c)
+			}
+		}
+	}
+	if len(q.Statuses) > 0 {
+		conds = append(conds, fmt.Sprintf(`status IN (%s)`, placeholders(len(q.Statuses))))
+		for _, st := range q.Statuses {
+			args = append(args, strings.ToUpper(st))
+		}
+	}
+	if q.MinConfidence > 0 {
+		conds = append(conds, `confidence >= ?`)
+		args = append(args, q.MinConfidence)
+	}
+	if q.MaxConfidence > 0 {
+		if q.MaxConfidence < q.MinConfidence {
+			return "", nil, fmt.Errorf("max confidence %.2f is below min confidence %.2f", q.MaxConfidence, q.MinConfidence)
+		}
+		conds = append(conds, `confidence <= ?`)
+		args = append(args, q.MaxConfidence)
+	}
+	if q.App != "" {
+		conds = append(conds, `id IN (SELECT event_id FROM event_apps WHERE name = ? COLLATE NOCASE)`)
+		args = append(args, q.App)
+	}
+	if q.Keyword != "" {
+		conds = append(conds, `id IN (SELECT event_id FROM event_keywords WHERE keyword = ? COLLATE NOCASE)`)
+		args = append(args, q.Keyword)
+	}
+
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// ListEvents returns the events matching q.
+func (s *Store) ListEvents(q EventQuery) ([]Event, error) {
+	where, args, err := q.where()
+	if err != nil {
+		return nil, err
+	}
+
+	order := "ASC"
+	switch q.Order {
+	case "", OrderAsc:
+	case OrderDesc:
+		order = "DESC"
+	default:
+		return nil, fmt.Errorf("unknown order: %s", q.Order)
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return nil, fmt.Errorf("limit and offset must be non-negative")
+	}
+
+	// Detected apps and keywords come from their tables, in recorded order.
+	query := `SELECT id, captured_at, category_id, category_name, confidence, status, agent_version, screenshot_hash,
+		(SELECT json_group_array(name ORDER BY position) FROM event_apps WHERE event_id = events.id),
+		(SELECT json_group_array(keyword ORDER BY position) FROM event_keywords WHERE event_id = events.id),
+		classified_by, classifier_provider, rationale, error_kind, error_message, classify_attempts, display_count, display_layout, resolution,
+		span_end, idle_seconds, notes, created_at
+		FROM events` + where + ` ORDER BY captured_at ` + order + `, id ` + order
+	if q.Limit > 0 || q.Offset > 0 {
+		// SQLite only accepts OFFSET after LIMIT; -1 means no limit.
+		limit := q.Limit
+		if limit == 0 {
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, q.Offset)
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []Event
+	for rows.Next() {
+		var e Event
+		var capturedAt string
+		var createdAt string
+		var detectedApps string
+		var detectedKeywords string
+		var categoryID, classifiedBy, classifierProvider, rationale, errorKind, errorMessage sql.NullString
+		var displayLayout, resolution, spanEnd, notes sql.NullString
+		var idleSeconds sql.NullInt64
+		if err := rows.Scan(&e.ID, &capturedAt, &categoryID, &e.CategoryName, &e.Confidence, &e.Status, &e.AgentVersion, &e.ScreenshotHash, &detectedApps, &detectedKeywords,
+			&classifiedBy, &classifierProvider, &rationale, &errorKind, &errorMessage, &e.ClassifyAttempts, &e.DisplayCount, &displayLayout, &resolution,
+			&spanEnd, &idleSeconds, &notes, &createdAt); err != nil {
+			return nil, err
+		}
+		e.CapturedAt, _ = time.Parse(time.RFC3339, capturedAt)
+		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		e.DetectedApps = decodeList(detectedApps)
+		e.DetectedKeywords = decodeList(detectedKeywords)
+		e.CategoryID = categoryID.String
+		e.ClassifiedBy = classifiedBy.String
+		e.ClassifierProvider = classifierProvider.String
+		e.Rationale = rationale.String
+		e.ErrorKind = errorKind.String
+		e.ErrorMessage = errorMessage.String
+		e.Resolution = resolution.String
+		if spanEnd.Valid {
+			e.SpanEnd, _ = time.Parse(time.RFC3339, spanEnd.String)
+		}
+		e.IdleSeconds = int(idleSeconds.Int64)
+		e.Notes = notes.String
+		if displayLayout.Valid {
+			_ = json.Unmarshal([]byte(displayLayout.String), &e.Displays)
+		}
+		results = append(results, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// decodeList decodes a JSON array of strings, returning nil when it is
+// empty.
+func decodeList(s string) []string {
+	var list []string
+	if err := json.Unmarshal([]byte(s), &list); err != nil || len(list) == 0 {
+		return nil
+	}
+	return list
+}
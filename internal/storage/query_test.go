@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateRangeDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		day  int // in March or November 2026
+		mon  time.Month
+		want time.Duration
+	}{
+		{8, time.March, 23 * time.Hour},
+		{1, time.November, 25 * time.Hour},
+		{9, time.March, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		date := time.Date(2026, tt.mon, tt.day, 12, 0, 0, 0, loc)
+		start, end := dateRangeUTC(date)
+		if got := end.Sub(start); got != tt.want {
+			t.Errorf("%s: length %v, want %v", date.Format("2006-01-02"), got, tt.want)
+		}
+		if local := end.In(loc); local.Hour() != 0 || local.Day() != tt.day+1 {
+			t.Errorf("%s: end %v is not the next midnight", date.Format("2006-01-02"), local)
+		}
+	}
+}
+
+func TestListEventsQuery(t *testing.T) {
+	s := openFixture(t, "")
+	if err := s.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2026, 1, 28, 9, 0, 0, 0, time.UTC)
+	events := []*Event{
+		{ID: "a", CategoryID: "implement", CategoryName: "実装", Confidence: 0.9, Status: "OK", DetectedApps: []string{"Code"}},
+		{ID: "b", CategoryID: "meeting", CategoryName: "会議", Confidence: 0.6, Status: "OK", DetectedApps: []string{"zoom"}, DetectedKeywords: []string{"standup"}},
+		{ID: "c", Status: "FAILED"},
+		{ID: "d", CategoryID: "implement", CategoryName: "実装", Confidence: 0.8, Status: "OK"},
+	}
+	for i, e := range events {
+		e.CapturedAt = base.Add(time.Duration(i) * 24 * time.Hour)
+		e.CreatedAt = e.CapturedAt
+		if err := s.InsertEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    EventQuery
+		want string
+	}{
+		{"all", EventQuery{}, "abcd"},
+		{"range", EventQuery{From: base.Add(24 * time.Hour), To: base.Add(72 * time.Hour)}, "bc"},
+		{"category id", EventQuery{Categories: []string{"implement"}}, "ad"},
+		{"category name", EventQuery{Categories: []string{"会議"}}, "b"},
+		{"status", EventQuery{Statuses: []string{"failed"}}, "c"},
+		{"confidence", EventQuery{MinConfidence: 0.7, MaxConfidence: 0.85}, "d"},
+		{"app", EventQuery{App: "code"}, "a"},
+		{"keyword", EventQuery{Keyword: "standup"}, "b"},
+		{"desc page", EventQuery{Order: OrderDesc, Limit: 2, Offset: 1}, "cb"},
+		{"offset only", EventQuery{Offset: 3}, "d"},
+	}
+	for _, tt := range tests {
+		got, err := s.ListEvents(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		ids := ""
+		for _, e := range got {
+			ids += e.ID
+		}
+		if ids != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, ids, tt.want)
+		}
+	}
+
+	if _, err := s.ListEvents(EventQuery{Order: "sideways"}); err == nil {
+		t.Error("unknown order should error")
+	}
+}
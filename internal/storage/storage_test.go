@@ -1,8 +1,11 @@
 package storage
 
 import (
+	"database/sql"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestOpen(t *testing.T) {
@@ -16,3 +19,145 @@ func TestOpen(t *testing.T) {
 		t.Error("nil")
 	}
 }
+
+func TestMigrateAddsDisplayColumns(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "old.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Table as created by the first release, before display columns existed.
+	if _, err := s.DB.Exec(`CREATE TABLE events (
+		id TEXT PRIMARY KEY, captured_at TEXT NOT NULL, category_name TEXT, confidence REAL,
+		status TEXT NOT NULL, agent_version TEXT, screenshot_hash TEXT, detected_apps TEXT,
+		detected_keywords TEXT, notes TEXT, created_at TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 1, 28, 10, 0, 0, 0, time.Local)
+	displays := []DisplayInfo{{Index: 0, Width: 1920, Height: 1080, Primary: true}, {Index: 1, X: 1920, Width: 2560, Height: 1440, Focused: true}}
+	if err := s.InsertEvent(&Event{ID: "1", CapturedAt: at, Status: "OK", DisplayCount: 2, Displays: displays, CreatedAt: at}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := s.ListEventsByDate(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].DisplayCount != 2 || len(events[0].Displays) != 2 || !events[0].Displays[1].Focused {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
+
+func TestUpdateClassification(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 1, 28, 10, 0, 0, 0, time.Local)
+	e := &Event{ID: "1", CapturedAt: at, Status: "FAILED", ErrorKind: "timeout", ClassifyAttempts: 1, DisplayCount: 1, CreatedAt: at}
+	if err := s.InsertEvent(e); err != nil {
+		t.Fatal(err)
+	}
+
+	e.Status, e.CategoryName, e.ErrorKind, e.ClassifyAttempts = "OK", "会議", "", 2
+	if err := s.UpdateClassification(e); err != nil {
+		t.Fatal(err)
+	}
+	events, err := s.ListEventsByDate(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Status != "OK" || events[0].CategoryName != "会議" || events[0].ClassifyAttempts != 2 {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	if err := s.UpdateClassification(&Event{ID: "missing"}); err == nil {
+		t.Error("updating a missing event should error")
+	}
+}
+
+func TestDetectedChildRows(t *testing.T) {
+	s := openFixture(t, "")
+	if err := s.Migrate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 1, 28, 10, 0, 0, 0, time.Local)
+	e := &Event{ID: "1", CapturedAt: at, CategoryID: "implement", Status: "OK", DetectedApps: []string{"code", "firefox"}, DisplayCount: 1, CreatedAt: at}
+	if err := s.InsertEvent(e); err != nil {
+		t.Fatal(err)
+	}
+	if got := detected(t, s, "1"); !reflect.DeepEqual(got, []string{"app:code", "app:firefox"}) {
+		t.Errorf("after insert: %v", got)
+	}
+
+	e.DetectedApps, e.DetectedKeywords = []string{"zoom"}, []string{"standup"}
+	if err := s.UpdateClassification(e); err != nil {
+		t.Fatal(err)
+	}
+	if got := detected(t, s, "1"); !reflect.DeepEqual(got, []string{"app:zoom", "keyword:standup"}) {
+		t.Errorf("after update: %v", got)
+	}
+
+	// Listing reads the child tables, not a JSON copy on the event.
+	var apps sql.NullString
+	if err := s.DB.QueryRow(`SELECT detected_apps FROM events WHERE id = '1'`).Scan(&apps); err != nil {
+		t.Fatal(err)
+	}
+	if apps.Valid {
+		t.Errorf("detected_apps written: %s", apps.String)
+	}
+	events, err := s.ListEventsByDate(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || !reflect.DeepEqual(events[0].DetectedApps, []string{"zoom"}) || !reflect.DeepEqual(events[0].DetectedKeywords, []string{"standup"}) {
+		t.Errorf("listed: %+v", events)
+	}
+
+	if _, err := s.DeleteEventsByDate(at); err != nil {
+		t.Fatal(err)
+	}
+	if got := detected(t, s, "1"); got != nil {
+		t.Errorf("after delete: %v", got)
+	}
+}
+
+func TestMigrateBackfillsCategoryIDsOnce(t *testing.T) {
+	s := openFixture(t, "v2.sql")
+	if err := s.Migrate(map[string]string{"実装": "implement"}); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 1, 28, 1, 0, 0, 0, time.UTC)
+	events, err := s.ListEventsByDate(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events[0].CategoryID != "implement" {
+		t.Errorf("event not backfilled: %+v", events[0])
+	}
+
+	// The backfill is part of migration 3 and does not run again.
+	if _, err := s.DB.Exec(`UPDATE events SET category_id = NULL`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Migrate(map[string]string{"実装": "implement"}); err != nil {
+		t.Fatal(err)
+	}
+	if events, err = s.ListEventsByDate(day); err != nil {
+		t.Fatal(err)
+	}
+	if events[0].CategoryID != "" {
+		t.Errorf("backfill ran again: %+v", events[0])
+	}
+}
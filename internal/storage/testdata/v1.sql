@@ -0,0 +1,17 @@
+-- Schema version 1.
+CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TEXT NOT NULL);
+INSERT INTO schema_migrations VALUES (1, 'create_events', '2026-01-01T00:00:00Z');
+CREATE TABLE events (
+	id TEXT PRIMARY KEY,
+	captured_at TEXT NOT NULL,
+	category_name TEXT,
+	confidence REAL,
+	status TEXT NOT NULL,
+	agent_version TEXT,
+	screenshot_hash TEXT,
+	detected_apps TEXT,
+	detected_keywords TEXT,
+	notes TEXT,
+	created_at TEXT NOT NULL
+);
+INSERT INTO events VALUES ('e1', '2026-01-28T01:00:00Z', '実装', 0.9, 'OK', 'gpt-4.1', 'abc', '["code"]', '["beholder"]', 'rationale=editing displayCount=1 resolution=1920x1080', '2026-01-28T01:00:00Z');
@@ -0,0 +1,47 @@
+-- Schema version 3.
+CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TEXT NOT NULL);
+INSERT INTO schema_migrations VALUES (1, 'create_events', '2026-01-01T00:00:00Z');
+INSERT INTO schema_migrations VALUES (2, 'add_display_and_classification_columns', '2026-01-01T00:00:00Z');
+INSERT INTO schema_migrations VALUES (3, 'normalize_events', '2026-01-01T00:00:00Z');
+CREATE TABLE events (
+	id TEXT PRIMARY KEY,
+	captured_at TEXT NOT NULL,
+	category_name TEXT,
+	confidence REAL,
+	status TEXT NOT NULL,
+	agent_version TEXT,
+	screenshot_hash TEXT,
+	detected_apps TEXT,
+	detected_keywords TEXT,
+	notes TEXT,
+	created_at TEXT NOT NULL,
+	display_count INTEGER NOT NULL DEFAULT 1,
+	display_layout TEXT,
+	classified_by TEXT,
+	error_kind TEXT,
+	error_message TEXT,
+	classify_attempts INTEGER NOT NULL DEFAULT 1,
+	category_id TEXT,
+	rationale TEXT,
+	resolution TEXT,
+	classifier_provider TEXT
+);
+CREATE INDEX idx_events_captured_at ON events (captured_at);
+CREATE TABLE event_apps (
+	event_id TEXT NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	PRIMARY KEY (event_id, position)
+);
+CREATE INDEX idx_event_apps_name ON event_apps (name);
+CREATE TABLE event_keywords (
+	event_id TEXT NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	keyword TEXT NOT NULL,
+	PRIMARY KEY (event_id, position)
+);
+CREATE INDEX idx_event_keywords_keyword ON event_keywords (keyword);
+INSERT INTO events (id, captured_at, category_id, category_name, confidence, status, agent_version, screenshot_hash, notes, created_at, display_count, display_layout, classified_by, error_kind, error_message, classify_attempts, rationale, resolution, classifier_provider)
+VALUES ('e1', '2026-01-28T01:00:00Z', 'implement', '実装', 0.9, 'OK', 'gpt-4.1', 'abc', '', '2026-01-28T01:00:00Z', 1, 'null', 'llm', '', '', 1, 'editing', '1920x1080', 'copilot');
+INSERT INTO event_apps VALUES ('e1', 0, 'code');
+INSERT INTO event_keywords VALUES ('e1', 0, 'beholder');
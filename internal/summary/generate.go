@@ -13,77 +13,127 @@ type CategorySummary struct {
 	CategoryName string
 	Count        int
 	Events       []storage.Event
+	Duration     time.Duration
+	LongestBlock Block
+	Hours        [24]time.Duration // by local hour of day
 }
 
 type DailySummary struct {
-	Date       time.Time
-	Categories []CategorySummary
-	TotalCount int
-	FirstAt    time.Time
-	LastAt     time.Time
+	Date          time.Time
+	Categories    []CategorySummary
+	TotalCount    int
+	FirstAt       time.Time
+	LastAt        time.Time
+	TotalDuration time.Duration
+	LongestBlock  Block
+	Hours         [24]time.Duration // by local hour of day
+	Spans         []Span
+	Sessions      []Session
+	Focus         Focus
 }
 
+// Options tunes how events are turned into time.
+type Options struct {
+	// MaxSpan caps how long one event can last, normally the longest
+	// scheduler interval. Zero means DefaultMaxSpan.
+	MaxSpan time.Duration
+
+	// FocusSession is the shortest session that counts towards the focus
+	// score. Zero means DefaultFocusSession.
+	FocusSession time.Duration
+}
+
+// Generate summarizes events over the local day of the first one.
 func Generate(events []storage.Event) *DailySummary {
-	if len(events) == 0 {
+	day := time.Now()
+	if len(events) > 0 {
+		day = events[0].CapturedAt.In(time.Local)
+	}
+	return GenerateWithOptions(DaysPeriod(day, day), events, Options{})
+}
+
+// GenerateWithOptions summarizes events over day. Time outside day, e.g.
+// the rest of an overnight suspend, is not counted.
+func GenerateWithOptions(day Period, events []storage.Event, opts Options) *DailySummary {
+	spans := Spans(events, opts.MaxSpan, day)
+	if len(spans) == 0 {
 		return &DailySummary{
-			Date:       time.Now().Local(),
+			Date:       day.Start,
 			Categories: []CategorySummary{},
 			TotalCount: 0,
 		}
 	}
 
-	firstAt := events[0].CapturedAt
-	lastAt := events[0].CapturedAt
-
-	categoryMap := make(map[string]*CategorySummary)
-
-	for _, event := range events {
-		if event.CapturedAt.Before(firstAt) {
-			firstAt = event.CapturedAt
-		}
-		if event.CapturedAt.After(lastAt) {
-			lastAt = event.CapturedAt
-		}
+	summary := fromSpans(spans, opts)
+	summary.Date = day.Start
+	return summary
+}
 
-		catName := event.CategoryName
-		if catName == "" {
-			catName = "未分類"
-		}
+// fromSpans builds the summary of spans, which must not be empty and must
+// lie within one day. Spans continued from an earlier day add time but are
+// not counted as events.
+func fromSpans(spans []Span, opts Options) *DailySummary {
+	sessions := Sessions(spans)
+	summary := &DailySummary{
+		Date:         spans[0].Start,
+		FirstAt:      spans[0].Start,
+		LastAt:       spans[len(spans)-1].Start,
+		LongestBlock: longestBlock(sessions, func(Session) bool { return true }),
+		Spans:        spans,
+		Sessions:     sessions,
+		Focus:        FocusOf(sessions, opts.FocusSession),
+	}
 
-		if _, exists := categoryMap[catName]; !exists {
-			categoryMap[catName] = &CategorySummary{
-				CategoryName: catName,
-				Count:        0,
+	categoryMap := make(map[string]*CategorySummary)
+	for _, span := range spans {
+		cat, exists := categoryMap[span.CategoryName]
+		if !exists {
+			cat = &CategorySummary{
+				CategoryName: span.CategoryName,
 				Events:       []storage.Event{},
 			}
+			categoryMap[span.CategoryName] = cat
+		}
+		if !span.continued() {
+			cat.Count++
+			summary.TotalCount++
 		}
+		cat.Events = append(cat.Events, span.Event)
+		cat.Duration += span.Duration()
+		addHours(&cat.Hours, span)
 
-		categoryMap[catName].Count++
-		categoryMap[catName].Events = append(categoryMap[catName].Events, event)
+		summary.TotalDuration += span.Duration()
+		addHours(&summary.Hours, span)
 	}
 
-	categorySummaries := make([]CategorySummary, 0, len(categoryMap))
-	for _, cat := range categoryMap {
-		categorySummaries = append(categorySummaries, *cat)
+	summary.Categories = make([]CategorySummary, 0, len(categoryMap))
+	for name, cat := range categoryMap {
+		cat.LongestBlock = longestBlock(sessions, func(s Session) bool { return s.CategoryName == name })
+		summary.Categories = append(summary.Categories, *cat)
 	}
-
-	sort.Slice(categorySummaries, func(i, j int) bool {
-		return categorySummaries[i].Count > categorySummaries[j].Count
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		a, b := summary.Categories[i], summary.Categories[j]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return a.CategoryName < b.CategoryName
 	})
+	return summary
+}
 
-	return &DailySummary{
-		Date:       events[0].CapturedAt.In(time.Local),
-		Categories: categorySummaries,
-		TotalCount: len(events),
-		FirstAt:    firstAt.In(time.Local),
-		LastAt:     lastAt.In(time.Local),
+// Share is the fraction of the total time spent in cat, as a percentage.
+func (s *DailySummary) Share(cat CategorySummary) float64 {
+	if s.TotalDuration <= 0 {
+		return 0
 	}
+	return float64(cat.Duration) / float64(s.TotalDuration) * 100
 }
 
 func (s *DailySummary) FormatMarkdown() string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("# Daily Report - %s\n\n", s.Date.Format("2006-01-02")))
+	sb.WriteString(fmt.Sprintf("**Total Time**: %s\n", FormatDuration(s.TotalDuration)))
 	sb.WriteString(fmt.Sprintf("**Total Events**: %d\n\n", s.TotalCount))
 	sb.WriteString(fmt.Sprintf("**First Classified**: %s\n", s.FirstAt.Format("15:04:05")))
 	sb.WriteString(fmt.Sprintf("**Last Classified**: %s\n\n", s.LastAt.Format("15:04:05")))
@@ -93,33 +143,40 @@ func (s *DailySummary) FormatMarkdown() string {
 		return sb.String()
 	}
 
+	sb.WriteString(fmt.Sprintf("**Longest Block**: %s %s\n\n", s.LongestBlock.CategoryName, formatBlock(s.LongestBlock)))
+
 	sb.WriteString("## Summary by Category\n\n")
 	for _, cat := range s.Categories {
 		sb.WriteString(fmt.Sprintf("### %s\n", cat.CategoryName))
-		sb.WriteString(fmt.Sprintf("- Count: %d\n", cat.Count))
-		sb.WriteString(fmt.Sprintf("- Percentage: %.1f%%\n\n", float64(cat.Count)/float64(s.TotalCount)*100))
+		sb.WriteString(fmt.Sprintf("- Time: %s\n", FormatDuration(cat.Duration)))
+		sb.WriteString(fmt.Sprintf("- Percentage: %.1f%%\n", s.Share(cat)))
+		sb.WriteString(fmt.Sprintf("- Events: %d\n", cat.Count))
+		sb.WriteString(fmt.Sprintf("- Longest Block: %s\n\n", formatBlock(cat.LongestBlock)))
 	}
 
-	sb.WriteString("## Timeline\n\n")
-	allEvents := []storage.Event{}
-	for _, cat := range s.Categories {
-		allEvents = append(allEvents, cat.Events...)
+	sb.WriteString("## By Hour\n\n")
+	sb.WriteString("| Hour | Time | Categories |\n")
+	sb.WriteString("|------|------|------------|\n")
+	for hour, d := range s.Hours {
+		if d <= 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("| %02d:00 | %s | %s |\n", hour, FormatDuration(d), s.hourBreakdown(hour)))
 	}
+	sb.WriteString("\n")
 
-	sort.Slice(allEvents, func(i, j int) bool {
-		return allEvents[i].CapturedAt.Before(allEvents[j].CapturedAt)
-	})
+	sb.WriteString("## Focus\n\n")
+	sb.WriteString(fmt.Sprintf("- Score: %.0f/100\n", s.Focus.Score))
+	sb.WriteString(fmt.Sprintf("- Context Switches: %d%s\n\n", s.Focus.Switches, s.peakSwitches()))
 
-	for _, event := range allEvents {
-		catName := event.CategoryName
-		if catName == "" {
-			catName = "未分類"
-		}
-		sb.WriteString(fmt.Sprintf("- %s | **%s** | confidence: %.2f | status: %s\n",
-			event.CapturedAt.In(time.Local).Format("15:04:05"),
-			catName,
-			event.Confidence,
-			event.Status))
+	sb.WriteString("## Timeline\n\n")
+	for _, session := range s.Sessions {
+		sb.WriteString(fmt.Sprintf("- %s-%s | %s | **%s**%s\n",
+			session.Start.Format("15:04"),
+			session.End.Format("15:04"),
+			FormatDuration(session.Duration()),
+			session.CategoryName,
+			sessionDetail(session)))
 	}
 
 	return sb.String()
@@ -130,7 +187,7 @@ func (s *DailySummary) FormatText() string {
 
 	sb.WriteString(fmt.Sprintf("Daily Report - %s\n", s.Date.Format("2006-01-02")))
 	sb.WriteString(strings.Repeat("=", 50) + "\n\n")
-	sb.WriteString(fmt.Sprintf("Total Events: %d\n\n", s.TotalCount))
+	sb.WriteString(fmt.Sprintf("Total Time: %s (%d events)\n\n", FormatDuration(s.TotalDuration), s.TotalCount))
 	sb.WriteString(fmt.Sprintf("First Classified: %s\n", s.FirstAt.Format("15:04:05")))
 	sb.WriteString(fmt.Sprintf("Last Classified: %s\n\n", s.LastAt.Format("15:04:05")))
 
@@ -139,14 +196,94 @@ func (s *DailySummary) FormatText() string {
 		return sb.String()
 	}
 
+	sb.WriteString(fmt.Sprintf("Longest Block: %s %s\n\n", s.LongestBlock.CategoryName, formatBlock(s.LongestBlock)))
+
 	sb.WriteString("Summary by Category:\n")
 	sb.WriteString(strings.Repeat("-", 50) + "\n")
 	for _, cat := range s.Categories {
-		sb.WriteString(fmt.Sprintf("%s: %d events (%.1f%%)\n",
+		sb.WriteString(fmt.Sprintf("%s: %s (%.1f%%), %d events, longest %s\n",
 			cat.CategoryName,
+			FormatDuration(cat.Duration),
+			s.Share(cat),
 			cat.Count,
-			float64(cat.Count)/float64(s.TotalCount)*100))
+			formatBlock(cat.LongestBlock)))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nFocus: %.0f/100, %d context switches%s\n", s.Focus.Score, s.Focus.Switches, s.peakSwitches()))
+
+	sb.WriteString("\nBy Hour:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	for hour, d := range s.Hours {
+		if d <= 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%02d:00  %7s  %s\n", hour, FormatDuration(d), s.hourBreakdown(hour)))
+	}
+
+	sb.WriteString("\nSessions:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	for _, session := range s.Sessions {
+		sb.WriteString(fmt.Sprintf("%s-%s  %7s  %s%s\n",
+			session.Start.Format("15:04"),
+			session.End.Format("15:04"),
+			FormatDuration(session.Duration()),
+			session.CategoryName,
+			sessionDetail(session)))
 	}
 
 	return sb.String()
 }
+
+// peakSwitches describes the hour with the most context switches, if any.
+func (s *DailySummary) peakSwitches() string {
+	peak := 0
+	for hour, n := range s.Focus.SwitchesPerHour {
+		if n > s.Focus.SwitchesPerHour[peak] {
+			peak = hour
+		}
+	}
+	if s.Focus.SwitchesPerHour[peak] == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (most at %02d:00: %d)", peak, s.Focus.SwitchesPerHour[peak])
+}
+
+// sessionDetail lists the dominant apps and keywords of session.
+func sessionDetail(session Session) string {
+	detail := strings.Join(append(append([]string{}, session.Apps...), session.Keywords...), ", ")
+	if detail == "" {
+		return ""
+	}
+	return " (" + detail + ")"
+}
+
+// hourBreakdown lists the categories active in hour, longest first.
+func (s *DailySummary) hourBreakdown(hour int) string {
+	cats := make([]CategorySummary, 0, len(s.Categories))
+	for _, cat := range s.Categories {
+		if cat.Hours[hour] > 0 {
+			cats = append(cats, cat)
+		}
+	}
+	sort.SliceStable(cats, func(i, j int) bool { return cats[i].Hours[hour] > cats[j].Hours[hour] })
+
+	parts := make([]string, 0, len(cats))
+	for _, cat := range cats {
+		parts = append(parts, fmt.Sprintf("%s %s", cat.CategoryName, FormatDuration(cat.Hours[hour])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDuration renders d rounded to the minute, e.g. "2h 05m" or "45m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %02dm", h, m)
+}
+
+func formatBlock(b Block) string {
+	return fmt.Sprintf("%s (%s-%s)", FormatDuration(b.Duration()), b.Start.Format("15:04"), b.End.Format("15:04"))
+}
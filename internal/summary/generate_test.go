@@ -1,6 +1,8 @@
 package summary
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,3 +15,119 @@ func TestGen(t *testing.T) {
 		t.Error("fail")
 	}
 }
+
+func TestGenerateDurations(t *testing.T) {
+	base := time.Date(2026, 1, 28, 9, 0, 0, 0, time.Local)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	events := []storage.Event{
+		{ID: "1", CapturedAt: at(0), CategoryName: "実装"},
+		{ID: "2", CapturedAt: at(10), CategoryName: "実装"},
+		{ID: "3", CapturedAt: at(20), CategoryName: "会議"},
+		{ID: "4", CapturedAt: at(25), CategoryName: "実装"},
+		// Nothing recorded from 09:35 to 09:55.
+		{ID: "5", CapturedAt: at(55), CategoryName: "実装"},
+		{ID: "6", CapturedAt: at(65), CategoryName: "離席", Status: "LOCKED", SpanEnd: at(105)},
+		{ID: "7", CapturedAt: at(110), CategoryName: "実装"},
+	}
+
+	s := GenerateWithOptions(DaysPeriod(base, base), events, Options{MaxSpan: 10 * time.Minute})
+	if s.TotalDuration != 95*time.Minute || s.TotalCount != 7 {
+		t.Errorf("total = %v over %d events", s.TotalDuration, s.TotalCount)
+	}
+
+	want := map[string]time.Duration{"実装": 50 * time.Minute, "会議": 5 * time.Minute, "離席": 40 * time.Minute}
+	for _, cat := range s.Categories {
+		if cat.Duration != want[cat.CategoryName] {
+			t.Errorf("%s: %v, want %v", cat.CategoryName, cat.Duration, want[cat.CategoryName])
+		}
+		if cat.CategoryName == "実装" && (cat.LongestBlock.Duration() != 20*time.Minute || !cat.LongestBlock.Start.Equal(at(0))) {
+			t.Errorf("実装 longest block = %+v", cat.LongestBlock)
+		}
+	}
+	if s.Categories[0].CategoryName != "実装" {
+		t.Errorf("categories should be sorted by time: %+v", s.Categories[0])
+	}
+	if s.LongestBlock.CategoryName != "離席" || s.LongestBlock.Duration() != 40*time.Minute {
+		t.Errorf("longest block = %+v", s.LongestBlock)
+	}
+	if s.Hours[9] != 40*time.Minute || s.Hours[10] != 55*time.Minute {
+		t.Errorf("hours 9 and 10 = %v, %v", s.Hours[9], s.Hours[10])
+	}
+
+	text := s.FormatText()
+	for _, line := range []string{"Total Time: 1h 35m (7 events)", "実装: 50m (52.6%), 5 events", "09:00      40m  実装 35m, 会議 5m"} {
+		if !strings.Contains(text, line) {
+			t.Errorf("text report missing %q:\n%s", line, text)
+		}
+	}
+	if md := s.FormatMarkdown(); !strings.Contains(md, "| 10:00 | 55m | 離席 40m, 実装 15m |") {
+		t.Errorf("markdown report missing hour row:\n%s", md)
+	}
+}
+
+func TestSessionsAndFocus(t *testing.T) {
+	base := time.Date(2026, 1, 28, 9, 0, 0, 0, time.Local)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	events := []storage.Event{
+		{ID: "1", CapturedAt: at(0), CategoryName: "実装", Status: "OK", DetectedApps: []string{"code", "firefox"}},
+		{ID: "2", CapturedAt: at(10), CategoryName: "実装", Status: "OK", DetectedApps: []string{"code"}, DetectedKeywords: []string{"beholder"}},
+		{ID: "3", CapturedAt: at(20), CategoryName: "会議", Status: "OK"},
+		{ID: "4", CapturedAt: at(25), CategoryName: "実装", Status: "OK"},
+		{ID: "5", CapturedAt: at(55), CategoryName: "実装", Status: "OK"},
+		{ID: "6", CapturedAt: at(65), CategoryName: "実装", Status: "IDLE"},
+	}
+
+	s := GenerateWithOptions(DaysPeriod(base, base), events, Options{MaxSpan: 10 * time.Minute, FocusSession: 15 * time.Minute})
+	if len(s.Sessions) != 5 {
+		t.Fatalf("want 5 sessions, got %+v", s.Sessions)
+	}
+	first := s.Sessions[0]
+	if !first.Start.Equal(at(0)) || first.Duration() != 20*time.Minute || len(first.Events) != 2 {
+		t.Errorf("first session = %+v", first)
+	}
+	if !reflect.DeepEqual(first.Apps, []string{"code", "firefox"}) || !reflect.DeepEqual(first.Keywords, []string{"beholder"}) {
+		t.Errorf("dominant apps %v, keywords %v", first.Apps, first.Keywords)
+	}
+	if !s.Sessions[4].Away() {
+		t.Errorf("idle session should be away: %+v", s.Sessions[4])
+	}
+
+	// 実装 -> 会議 -> 実装 before 09:35; the gap and the idle session do
+	// not count.
+	if s.Focus.Switches != 2 || s.Focus.SwitchesPerHour[9] != 2 {
+		t.Errorf("switches = %d, per hour %v", s.Focus.Switches, s.Focus.SwitchesPerHour)
+	}
+	// 20m of the 45m active time was in sessions of 15m or more.
+	if got := s.Focus.Score; got < 44.4 || got > 44.5 {
+		t.Errorf("focus score = %.2f", got)
+	}
+
+	if md := s.FormatMarkdown(); !strings.Contains(md, "- 09:00-09:20 | 20m | **実装** (code, firefox, beholder)\n") {
+		t.Errorf("markdown timeline missing session:\n%s", md)
+	}
+}
+
+func TestGenerateClipsToDay(t *testing.T) {
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 1, day, hour, min, 0, 0, time.Local) }
+	resumed := at(29, 8, 0)
+	events := []storage.Event{
+		{ID: "1", CapturedAt: at(28, 22, 0), CategoryName: "実装", Status: "OK"},
+		{ID: "2", CapturedAt: at(28, 22, 10), CategoryName: "離席", Status: "SUSPENDED", SpanEnd: resumed},
+	}
+
+	s := GenerateWithOptions(DaysPeriod(at(28, 0, 0), at(28, 0, 0)), events, Options{MaxSpan: 10 * time.Minute})
+	if s.TotalDuration != 2*time.Hour || s.TotalCount != 2 {
+		t.Errorf("total = %v over %d events", s.TotalDuration, s.TotalCount)
+	}
+	if !s.Spans[1].End.Equal(at(29, 0, 0)) {
+		t.Errorf("suspend should end at midnight, got %v", s.Spans[1].End)
+	}
+	for hour, d := range s.Hours {
+		if hour < 22 && d != 0 {
+			t.Errorf("hour %d = %v, want nothing before 22:00", hour, d)
+		}
+	}
+	if s.Hours[22] != time.Hour || s.Hours[23] != time.Hour {
+		t.Errorf("hours 22 and 23 = %v, %v", s.Hours[22], s.Hours[23])
+	}
+}
@@ -0,0 +1,263 @@
+package summary
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var reportTemplate = template.Must(template.ParseFS(templateFS, "templates/report.html.tmpl"))
+
+// palette colours categories that set no color of their own.
+var palette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f"}
+
+// otherColor is used for categories not in the config, e.g. 未分類.
+const otherColor = "#bab0ac"
+
+// Timeline chart geometry, in SVG user units.
+const (
+	chartWidth  = 960
+	labelWidth  = 90
+	chartMargin = 20 // keeps the first and last hour labels inside
+	rowHeight   = 22
+	rowGap      = 6
+	axisHeight  = 20
+)
+
+// CategoryColors maps category names to their configured color, falling
+// back to a fixed palette in config order.
+func CategoryColors(categories []config.CategoryConfig) map[string]string {
+	colors := make(map[string]string, len(categories))
+	for i, c := range categories {
+		color := c.Color
+		if color == "" {
+			color = palette[i%len(palette)]
+		}
+		colors[c.Name] = color
+	}
+	return colors
+}
+
+type htmlReport struct {
+	Title    string
+	Subtitle string
+	Stats    []htmlStat
+	Timeline htmlTimeline
+	Donut    []htmlSlice
+	Events   []htmlEvent
+}
+
+type htmlStat struct {
+	Label string
+	Value string
+}
+
+type htmlTimeline struct {
+	Width     int
+	Height    int
+	Label     int // x where the plot starts
+	RowHeight int
+	Rows      []htmlRow
+	Ticks     []htmlTick
+}
+
+type htmlRow struct {
+	Label    string
+	Y        int
+	Segments []htmlSegment
+}
+
+type htmlSegment struct {
+	X, Width string
+	Color    string
+	Title    string
+}
+
+type htmlTick struct {
+	X     string
+	Label string
+}
+
+type htmlSlice struct {
+	Name     string
+	Color    string
+	Share    string
+	Duration string
+	Dash     string // stroke-dasharray on a circle with a circumference of 100
+	Offset   string
+
+	percent float64
+}
+
+type htmlEvent struct {
+	Time        string
+	TimeSort    string // local time as digits, so sorting ignores the zone
+	Duration    string
+	DurationSec int64
+	Category    string
+	Color       string
+	Confidence  string
+	Status      string
+	Apps        string
+}
+
+// FormatHTML renders the summary as a standalone HTML page. colors maps
+// category names to CSS colors, see CategoryColors.
+func (s *DailySummary) FormatHTML(colors map[string]string) (string, error) {
+	r := htmlReport{
+		Title: "Daily Report - " + s.Date.Format("2006-01-02"),
+		Stats: []htmlStat{
+			{"Total Time", FormatDuration(s.TotalDuration)},
+			{"Events", fmt.Sprint(s.TotalCount)},
+			{"Focus", fmt.Sprintf("%.0f/100", s.Focus.Score)},
+			{"Context Switches", fmt.Sprint(s.Focus.Switches)},
+		},
+	}
+	if s.TotalCount > 0 {
+		r.Subtitle = fmt.Sprintf("%s - %s", s.FirstAt.Format("15:04"), s.LastAt.Format("15:04"))
+		r.Stats = append(r.Stats, htmlStat{"Longest Block", s.LongestBlock.CategoryName + " " + formatBlock(s.LongestBlock)})
+	}
+	r.Timeline = timeline([]*DailySummary{s}, "", colors)
+	for _, cat := range s.Categories {
+		r.Donut = append(r.Donut, newSlice(cat.CategoryName, colorOf(colors, cat.CategoryName), s.Share(cat), cat.Duration))
+	}
+	r.Events = htmlEvents(s.Spans, colors, "15:04:05")
+	return render(r)
+}
+
+// FormatHTML renders the report as a standalone HTML page with one
+// timeline row per day.
+func (r *RangeSummary) FormatHTML(colors map[string]string) (string, error) {
+	out := htmlReport{
+		Title:    r.title(),
+		Subtitle: r.dateRange(),
+		Stats: []htmlStat{
+			{"Total Time", FormatDuration(r.TotalDuration)},
+			{"Events", fmt.Sprint(r.TotalCount)},
+			{"Previous Period", r.comparison()},
+			{"Focus", fmt.Sprintf("%.0f/100", r.Focus.Score)},
+			{"Context Switches", fmt.Sprint(r.Focus.Switches)},
+		},
+	}
+	out.Timeline = timeline(r.Days, "01-02 Mon", colors)
+	for _, cat := range r.Categories {
+		if cat.Duration <= 0 {
+			continue
+		}
+		out.Donut = append(out.Donut, newSlice(cat.CategoryName, colorOf(colors, cat.CategoryName), r.Share(cat), cat.Duration))
+	}
+	var spans []Span
+	for _, day := range r.Days {
+		spans = append(spans, day.Spans...)
+	}
+	out.Events = htmlEvents(spans, colors, "2006-01-02 15:04")
+	return render(out)
+}
+
+func newSlice(name, color string, percent float64, d time.Duration) htmlSlice {
+	return htmlSlice{Name: name, Color: color, Share: fmt.Sprintf("%.1f%%", percent), Duration: FormatDuration(d), percent: percent}
+}
+
+func render(r htmlReport) (string, error) {
+	offset := 25.0 // start the donut at 12 o'clock
+	for i := range r.Donut {
+		p := r.Donut[i].percent
+		r.Donut[i].Dash = fmt.Sprintf("%.2f %.2f", p, 100-p)
+		r.Donut[i].Offset = fmt.Sprintf("%.2f", offset)
+		offset -= p
+	}
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// timeline lays out one row per day across the hours that have any
+// activity. label formats the row label; empty means no label.
+func timeline(days []*DailySummary, label string, colors map[string]string) htmlTimeline {
+	first, last := 24*60, 0
+	for _, day := range days {
+		for _, s := range day.Spans {
+			start, end := minuteOfDay(day.Date, s.Start), minuteOfDay(day.Date, s.End)
+			first, last = min(first, start), max(last, end)
+		}
+	}
+	if first >= last {
+		first, last = 9*60, 18*60
+	}
+	first, last = first/60*60, min((last+59)/60*60, 24*60)
+
+	t := htmlTimeline{Width: chartWidth, Label: labelWidth, RowHeight: rowHeight}
+	if label == "" {
+		t.Label = chartMargin
+	}
+	plot := float64(chartWidth - t.Label - chartMargin)
+	x := func(minute int) float64 { return float64(t.Label) + float64(minute-first)/float64(last-first)*plot }
+
+	for h := first; h <= last; h += 60 {
+		t.Ticks = append(t.Ticks, htmlTick{X: fmt.Sprintf("%.1f", x(h)), Label: fmt.Sprintf("%02d:00", h/60)})
+	}
+	for i, day := range days {
+		row := htmlRow{Y: axisHeight + i*(rowHeight+rowGap)}
+		if label != "" {
+			row.Label = day.Date.Format(label)
+		}
+		for _, s := range day.Spans {
+			start, end := max(minuteOfDay(day.Date, s.Start), first), min(minuteOfDay(day.Date, s.End), last)
+			if end <= start {
+				continue
+			}
+			row.Segments = append(row.Segments, htmlSegment{
+				X:     fmt.Sprintf("%.1f", x(start)),
+				Width: fmt.Sprintf("%.1f", x(end)-x(start)),
+				Color: colorOf(colors, s.CategoryName),
+				Title: fmt.Sprintf("%s-%s %s (%s)", s.Start.Format("15:04"), s.End.Format("15:04"), s.CategoryName, FormatDuration(s.Duration())),
+			})
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	t.Height = axisHeight + len(days)*(rowHeight+rowGap)
+	return t
+}
+
+// minuteOfDay is t as minutes since the local midnight starting day,
+// capped at the end of the day.
+func minuteOfDay(day, t time.Time) int {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, t.Location())
+	return min(int(t.Sub(midnight)/time.Minute), 24*60)
+}
+
+func htmlEvents(spans []Span, colors map[string]string, layout string) []htmlEvent {
+	events := make([]htmlEvent, 0, len(spans))
+	for _, s := range spans {
+		events = append(events, htmlEvent{
+			Time:        s.Start.Format(layout),
+			TimeSort:    s.Start.Format("20060102150405"),
+			Duration:    FormatDuration(s.Duration()),
+			DurationSec: int64(s.Duration().Seconds()),
+			Category:    s.CategoryName,
+			Color:       colorOf(colors, s.CategoryName),
+			Confidence:  fmt.Sprintf("%.2f", s.Event.Confidence),
+			Status:      s.Event.Status,
+			Apps:        strings.Join(s.Event.DetectedApps, ", "),
+		})
+	}
+	return events
+}
+
+func colorOf(colors map[string]string, name string) string {
+	if c, ok := colors[name]; ok {
+		return c
+	}
+	return otherColor
+}
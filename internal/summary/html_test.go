@@ -0,0 +1,69 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/storage"
+	"github.com/aknow2/beholder/internal/testutil"
+)
+
+var htmlCategories = []config.CategoryConfig{
+	{ID: "implement", Name: "実装", Color: "#4e79a7"},
+	{ID: "meeting", Name: "会議 <&>"},
+	{ID: "afk", Name: "離席", Color: "#bab0ac"},
+}
+
+func sampleEvents(day int) []storage.Event {
+	at := func(hour, min int) time.Time { return time.Date(2026, 1, day, hour, min, 0, 0, time.Local) }
+	return []storage.Event{
+		{ID: "1", CapturedAt: at(9, 0), CategoryName: "実装", Confidence: 0.9, Status: "OK", DetectedApps: []string{"code"}},
+		{ID: "2", CapturedAt: at(9, 10), CategoryName: "実装", Confidence: 0.8, Status: "OK", DetectedApps: []string{"code", "firefox"}},
+		{ID: "3", CapturedAt: at(9, 20), CategoryName: "会議 <&>", Confidence: 0.7, Status: "OK", DetectedApps: []string{"<script>zoom</script>"}},
+		{ID: "4", CapturedAt: at(9, 30), CategoryName: "離席", Confidence: 1, Status: "IDLE"},
+		{ID: "5", CapturedAt: at(10, 15), Status: "FAILED"},
+	}
+}
+
+func sampleDay(day int) Period {
+	d := time.Date(2026, 1, day, 0, 0, 0, 0, time.Local)
+	return DaysPeriod(d, d)
+}
+
+func TestDailyHTMLGolden(t *testing.T) {
+	s := GenerateWithOptions(sampleDay(28), sampleEvents(28), Options{MaxSpan: 10 * time.Minute})
+	got, err := s.FormatHTML(CategoryColors(htmlCategories))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "<script>zoom") || strings.Contains(got, "会議 <&>") {
+		t.Error("category and app names must be escaped")
+	}
+	testutil.CheckGolden(t, "daily.html.golden", []byte(got))
+}
+
+func TestRangeHTMLGolden(t *testing.T) {
+	p, err := WeekPeriod("2026-W05", time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := append(sampleEvents(26), sampleEvents(28)[:2]...)
+	r := GenerateRange(p, events, sampleEvents(20), Options{MaxSpan: 10 * time.Minute})
+	got, err := r.FormatHTML(CategoryColors(htmlCategories))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.CheckGolden(t, "range.html.golden", []byte(got))
+}
+
+func TestEmptyHTML(t *testing.T) {
+	got, err := Generate(nil).FormatHTML(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "No events recorded.") || strings.Contains(got, "<svg") {
+		t.Errorf("unexpected empty report:\n%s", got)
+	}
+}
@@ -0,0 +1,90 @@
+package summary
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	PeriodDays  = "days"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Period is a run of whole local days, [Start, End).
+type Period struct {
+	Kind  string // PeriodDays, PeriodWeek or PeriodMonth
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// WeekPeriod parses an ISO 8601 week such as "2026-W42". Weeks start on
+// Monday.
+func WeekPeriod(s string, loc *time.Location) (Period, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(s, "%d-W%d", &year, &week); err != nil || fmt.Sprintf("%04d-W%02d", year, week) != s {
+		return Period{}, fmt.Errorf("invalid week %q: want YYYY-Www", s)
+	}
+	// 4 January is always in week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return Period{}, fmt.Errorf("invalid week %q: %d has no week %d", s, year, week)
+	}
+	return weekStarting(monday), nil
+}
+
+// MonthPeriod parses a month such as "2026-10".
+func MonthPeriod(s string, loc *time.Location) (Period, error) {
+	t, err := time.ParseInLocation("2006-01", s, loc)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid month %q: want YYYY-MM", s)
+	}
+	return monthStarting(t), nil
+}
+
+// DaysPeriod covers the local days from and to, both included.
+func DaysPeriod(from, to time.Time) Period {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	last := to.In(from.Location())
+	end := time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, from.Location())
+	return Period{
+		Kind:  PeriodDays,
+		Label: fmt.Sprintf("%s..%s", start.Format("2006-01-02"), last.Format("2006-01-02")),
+		Start: start,
+		End:   end,
+	}
+}
+
+func weekStarting(monday time.Time) Period {
+	y, w := monday.ISOWeek()
+	return Period{Kind: PeriodWeek, Label: fmt.Sprintf("%04d-W%02d", y, w), Start: monday, End: monday.AddDate(0, 0, 7)}
+}
+
+func monthStarting(first time.Time) Period {
+	return Period{Kind: PeriodMonth, Label: first.Format("2006-01"), Start: first, End: first.AddDate(0, 1, 0)}
+}
+
+// Previous is the period of the same kind just before p: the previous week
+// or month, or as many days as p ending where p starts.
+func (p Period) Previous() Period {
+	switch p.Kind {
+	case PeriodWeek:
+		return weekStarting(p.Start.AddDate(0, 0, -7))
+	case PeriodMonth:
+		return monthStarting(p.Start.AddDate(0, -1, 0))
+	default:
+		days := len(p.Days())
+		return DaysPeriod(p.Start.AddDate(0, 0, -days), p.Start.AddDate(0, 0, -1))
+	}
+}
+
+// Days lists local midnight of every day in p.
+func (p Period) Days() []time.Time {
+	var days []time.Time
+	for d := p.Start; d.Before(p.End); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
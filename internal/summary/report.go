@@ -0,0 +1,272 @@
+package summary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+// RangeSummary aggregates a multi-day Period and compares it with the
+// period before.
+type RangeSummary struct {
+	Period        Period
+	Days          []*DailySummary // one per day of Period, empty days included
+	Categories    []CategoryTrend
+	TotalDuration time.Duration
+	TotalCount    int
+	Focus         Focus
+
+	// WeekdayAverage is the mean tracked time per weekday, indexed by
+	// time.Weekday, over the days that have any events. WeekdayDays
+	// counts those days.
+	WeekdayAverage [7]time.Duration
+	WeekdayDays    [7]int
+
+	Previous         Period
+	PreviousDuration time.Duration
+}
+
+// CategoryTrend is one category across a RangeSummary.
+type CategoryTrend struct {
+	CategoryName string
+	Duration     time.Duration
+	Count        int
+	Daily        []time.Duration // aligned with RangeSummary.Days
+	Previous     time.Duration   // in the previous period
+}
+
+// Change is the difference from the previous period.
+func (c CategoryTrend) Change() time.Duration {
+	return c.Duration - c.Previous
+}
+
+// GenerateRange summarizes events over p. previous are the events of
+// p.Previous() and are only used for comparison.
+func GenerateRange(p Period, events, previous []storage.Event, opts Options) *RangeSummary {
+	r := &RangeSummary{Period: p, Previous: p.Previous()}
+
+	spans := Spans(events, opts.MaxSpan, p)
+
+	trends := map[string]*CategoryTrend{}
+	days := p.Days()
+	for i, day := range days {
+		daySpans := clipSpans(spans, day, day.AddDate(0, 0, 1))
+		summary := &DailySummary{Date: day, Categories: []CategorySummary{}}
+		if len(daySpans) > 0 {
+			summary = fromSpans(daySpans, opts)
+			summary.Date = day
+			r.WeekdayAverage[day.Weekday()] += summary.TotalDuration
+			r.WeekdayDays[day.Weekday()]++
+		}
+		r.Days = append(r.Days, summary)
+		r.TotalDuration += summary.TotalDuration
+		r.TotalCount += summary.TotalCount
+
+		for _, cat := range summary.Categories {
+			t, ok := trends[cat.CategoryName]
+			if !ok {
+				t = &CategoryTrend{CategoryName: cat.CategoryName, Daily: make([]time.Duration, len(days))}
+				trends[cat.CategoryName] = t
+			}
+			t.Duration += cat.Duration
+			t.Count += cat.Count
+			t.Daily[i] = cat.Duration
+		}
+	}
+	for wd, n := range r.WeekdayDays {
+		if n > 0 {
+			r.WeekdayAverage[wd] /= time.Duration(n)
+		}
+	}
+	r.Focus = FocusOf(Sessions(spans), opts.FocusSession)
+
+	for _, s := range Spans(previous, opts.MaxSpan, r.Previous) {
+		r.PreviousDuration += s.Duration()
+		t, ok := trends[s.CategoryName]
+		if !ok {
+			t = &CategoryTrend{CategoryName: s.CategoryName, Daily: make([]time.Duration, len(days))}
+			trends[s.CategoryName] = t
+		}
+		t.Previous += s.Duration()
+	}
+
+	r.Categories = make([]CategoryTrend, 0, len(trends))
+	for _, t := range trends {
+		r.Categories = append(r.Categories, *t)
+	}
+	sort.Slice(r.Categories, func(i, j int) bool {
+		a, b := r.Categories[i], r.Categories[j]
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		if a.Previous != b.Previous {
+			return a.Previous > b.Previous
+		}
+		return a.CategoryName < b.CategoryName
+	})
+	return r
+}
+
+// clipSpans returns the parts of spans within [from, to), so a span that
+// crosses midnight counts towards both days.
+func clipSpans(spans []Span, from, to time.Time) []Span {
+	var clipped []Span
+	for _, s := range spans {
+		if c, ok := s.clip(from, to); ok {
+			clipped = append(clipped, c)
+		}
+	}
+	return clipped
+}
+
+// Share is the fraction of the total time spent in cat, as a percentage.
+func (r *RangeSummary) Share(cat CategoryTrend) float64 {
+	if r.TotalDuration <= 0 {
+		return 0
+	}
+	return float64(cat.Duration) / float64(r.TotalDuration) * 100
+}
+
+func (r *RangeSummary) title() string {
+	switch r.Period.Kind {
+	case PeriodWeek:
+		return "Weekly Report - " + r.Period.Label
+	case PeriodMonth:
+		return "Monthly Report - " + r.Period.Label
+	default:
+		return "Report - " + r.Period.Label
+	}
+}
+
+func (r *RangeSummary) dateRange() string {
+	return fmt.Sprintf("%s - %s", r.Period.Start.Format("2006-01-02"), r.Period.End.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
+// comparison describes the total against the previous period.
+func (r *RangeSummary) comparison() string {
+	change := formatChange(r.TotalDuration - r.PreviousDuration)
+	if r.PreviousDuration > 0 {
+		change += fmt.Sprintf(", %+.1f%%", float64(r.TotalDuration-r.PreviousDuration)/float64(r.PreviousDuration)*100)
+	}
+	return fmt.Sprintf("%s in %s (%s)", FormatDuration(r.PreviousDuration), r.Previous.Label, change)
+}
+
+func (r *RangeSummary) FormatMarkdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", r.title()))
+	sb.WriteString(fmt.Sprintf("**Period**: %s\n\n", r.dateRange()))
+	sb.WriteString(fmt.Sprintf("**Total Time**: %s\n\n", FormatDuration(r.TotalDuration)))
+	sb.WriteString(fmt.Sprintf("**Total Events**: %d\n\n", r.TotalCount))
+	sb.WriteString(fmt.Sprintf("**Previous Period**: %s\n\n", r.comparison()))
+	sb.WriteString(fmt.Sprintf("**Focus**: %.0f/100, %d context switches\n\n", r.Focus.Score, r.Focus.Switches))
+
+	if r.TotalCount == 0 {
+		sb.WriteString("No events recorded.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("## Summary by Category\n\n")
+	sb.WriteString("| Category | Time | Percentage | Events | Previous | Change |\n")
+	sb.WriteString("|----------|------|------------|--------|----------|--------|\n")
+	for _, cat := range r.Categories {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %.1f%% | %d | %s | %s |\n",
+			cat.CategoryName, FormatDuration(cat.Duration), r.Share(cat), cat.Count, FormatDuration(cat.Previous), formatChange(cat.Change())))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Per Day\n\n")
+	sb.WriteString("| Date | Total |")
+	for _, cat := range r.Categories {
+		sb.WriteString(fmt.Sprintf(" %s |", cat.CategoryName))
+	}
+	sb.WriteString("\n|------|-------|")
+	sb.WriteString(strings.Repeat("------|", len(r.Categories)))
+	sb.WriteString("\n")
+	for i, day := range r.Days {
+		sb.WriteString(fmt.Sprintf("| %s | %s |", day.Date.Format("01-02 Mon"), FormatDuration(day.TotalDuration)))
+		for _, cat := range r.Categories {
+			sb.WriteString(fmt.Sprintf(" %s |", FormatDuration(cat.Daily[i])))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Day-of-Week Average\n\n")
+	sb.WriteString("| Day | Average | Days |\n")
+	sb.WriteString("|-----|---------|------|\n")
+	for _, wd := range weekdays {
+		if r.WeekdayDays[wd] == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d |\n", wd.String()[:3], FormatDuration(r.WeekdayAverage[wd]), r.WeekdayDays[wd]))
+	}
+
+	return sb.String()
+}
+
+func (r *RangeSummary) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s (%s)\n", r.title(), r.dateRange()))
+	sb.WriteString(strings.Repeat("=", 50) + "\n\n")
+	sb.WriteString(fmt.Sprintf("Total Time: %s (%d events)\n", FormatDuration(r.TotalDuration), r.TotalCount))
+	sb.WriteString(fmt.Sprintf("Previous Period: %s\n", r.comparison()))
+	sb.WriteString(fmt.Sprintf("Focus: %.0f/100, %d context switches\n\n", r.Focus.Score, r.Focus.Switches))
+
+	if r.TotalCount == 0 {
+		sb.WriteString("No events recorded.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("Summary by Category:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	for _, cat := range r.Categories {
+		sb.WriteString(fmt.Sprintf("%s: %s (%.1f%%), %d events, previous %s (%s)\n",
+			cat.CategoryName,
+			FormatDuration(cat.Duration),
+			r.Share(cat),
+			cat.Count,
+			FormatDuration(cat.Previous),
+			formatChange(cat.Change())))
+	}
+
+	sb.WriteString("\nPer Day:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	for _, day := range r.Days {
+		sb.WriteString(fmt.Sprintf("%s  %7s", day.Date.Format("01-02 Mon"), FormatDuration(day.TotalDuration)))
+		var parts []string
+		for _, cat := range day.Categories {
+			parts = append(parts, fmt.Sprintf("%s %s", cat.CategoryName, FormatDuration(cat.Duration)))
+		}
+		if len(parts) > 0 {
+			sb.WriteString("  " + strings.Join(parts, ", "))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\nDay-of-Week Average:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	for _, wd := range weekdays {
+		if r.WeekdayDays[wd] == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s  %7s  (%d days)\n", wd.String()[:3], FormatDuration(r.WeekdayAverage[wd]), r.WeekdayDays[wd]))
+	}
+
+	return sb.String()
+}
+
+// weekdays is the display order, Monday first.
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// formatChange renders a signed duration, e.g. "+1h 05m" or "-20m".
+func formatChange(d time.Duration) string {
+	if d < 0 {
+		return "-" + FormatDuration(-d)
+	}
+	return "+" + FormatDuration(d)
+}
@@ -0,0 +1,112 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+func TestPeriods(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name       string
+		period     func() (Period, error)
+		start, end time.Time
+		previous   string
+	}{
+		{"week", func() (Period, error) { return WeekPeriod("2026-W42", time.Local) }, day(2026, 10, 12), day(2026, 10, 19), "2026-W41"},
+		{"first week", func() (Period, error) { return WeekPeriod("2026-W01", time.Local) }, day(2025, 12, 29), day(2026, 1, 5), "2025-W52"},
+		{"month", func() (Period, error) { return MonthPeriod("2026-03", time.Local) }, day(2026, 3, 1), day(2026, 4, 1), "2026-02"},
+		{"days", func() (Period, error) { return DaysPeriod(day(2026, 10, 1), day(2026, 10, 3)), nil }, day(2026, 10, 1), day(2026, 10, 4), "2026-09-28..2026-09-30"},
+	}
+	for _, tt := range tests {
+		p, err := tt.period()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !p.Start.Equal(tt.start) || !p.End.Equal(tt.end) {
+			t.Errorf("%s: [%v, %v), want [%v, %v)", tt.name, p.Start, p.End, tt.start, tt.end)
+		}
+		if got := p.Previous().Label; got != tt.previous {
+			t.Errorf("%s: previous = %s, want %s", tt.name, got, tt.previous)
+		}
+	}
+
+	for _, bad := range []string{"2025-W53", "2026-W0", "2026-42"} {
+		if _, err := WeekPeriod(bad, time.Local); err == nil {
+			t.Errorf("WeekPeriod(%q) should error", bad)
+		}
+	}
+}
+
+func TestGenerateRange(t *testing.T) {
+	p, err := WeekPeriod("2026-W42", time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 10, day, hour, min, 0, 0, time.Local) }
+	events := []storage.Event{
+		{ID: "1", CapturedAt: at(12, 9, 0), CategoryName: "実装", Status: "OK"},
+		{ID: "2", CapturedAt: at(12, 9, 10), CategoryName: "会議", Status: "OK"},
+		{ID: "3", CapturedAt: at(14, 9, 0), CategoryName: "実装", Status: "OK"},
+	}
+	previous := []storage.Event{
+		{ID: "p", CapturedAt: at(5, 9, 0), CategoryName: "会議", Status: "OK"},
+	}
+
+	r := GenerateRange(p, events, previous, Options{MaxSpan: 10 * time.Minute})
+	if len(r.Days) != 7 || r.TotalDuration != 30*time.Minute || r.TotalCount != 3 {
+		t.Fatalf("days=%d total=%v count=%d", len(r.Days), r.TotalDuration, r.TotalCount)
+	}
+	if r.Days[0].TotalDuration != 20*time.Minute || r.Days[1].TotalDuration != 0 || r.Days[2].TotalDuration != 10*time.Minute {
+		t.Errorf("per day = %v, %v, %v", r.Days[0].TotalDuration, r.Days[1].TotalDuration, r.Days[2].TotalDuration)
+	}
+	if r.WeekdayAverage[time.Monday] != 20*time.Minute || r.WeekdayDays[time.Tuesday] != 0 {
+		t.Errorf("weekday average = %v", r.WeekdayAverage)
+	}
+	if r.PreviousDuration != 10*time.Minute {
+		t.Errorf("previous = %v", r.PreviousDuration)
+	}
+
+	impl, meeting := r.Categories[0], r.Categories[1]
+	if impl.CategoryName != "実装" || impl.Duration != 20*time.Minute || impl.Daily[2] != 10*time.Minute || impl.Change() != 20*time.Minute {
+		t.Errorf("実装 trend = %+v", impl)
+	}
+	if meeting.Previous != 10*time.Minute || meeting.Change() != 0 {
+		t.Errorf("会議 trend = %+v", meeting)
+	}
+
+	text := r.FormatText()
+	for _, want := range []string{"Weekly Report - 2026-W42 (2026-10-12 - 2026-10-18)", "Previous Period: 10m in 2026-W41 (+20m, +200.0%)", "10-12 Mon      20m  会議 10m, 実装 10m"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text report missing %q:\n%s", want, text)
+		}
+	}
+	if md := r.FormatMarkdown(); !strings.Contains(md, "| 10-14 Wed | 10m | 10m | 0m |") {
+		t.Errorf("markdown report missing day row:\n%s", md)
+	}
+}
+
+func TestGenerateRangeSplitsDays(t *testing.T) {
+	p := DaysPeriod(time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local), time.Date(2026, 10, 13, 0, 0, 0, 0, time.Local))
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 10, day, hour, min, 0, 0, time.Local) }
+	events := []storage.Event{
+		{ID: "1", CapturedAt: at(12, 23, 0), CategoryName: "離席", Status: "SUSPENDED", SpanEnd: at(13, 8, 0)},
+		{ID: "2", CapturedAt: at(13, 8, 0), CategoryName: "実装", Status: "OK"},
+	}
+
+	r := GenerateRange(p, events, nil, Options{MaxSpan: 10 * time.Minute})
+	monday, tuesday := r.Days[0], r.Days[1]
+	if monday.TotalDuration != time.Hour || monday.TotalCount != 1 {
+		t.Errorf("monday = %v over %d events", monday.TotalDuration, monday.TotalCount)
+	}
+	if tuesday.TotalDuration != 8*time.Hour+10*time.Minute || tuesday.TotalCount != 1 || tuesday.Hours[0] != time.Hour {
+		t.Errorf("tuesday = %v over %d events, 00:00 = %v", tuesday.TotalDuration, tuesday.TotalCount, tuesday.Hours[0])
+	}
+	if r.TotalCount != 2 || r.TotalDuration != 9*time.Hour+10*time.Minute {
+		t.Errorf("total = %v over %d events", r.TotalDuration, r.TotalCount)
+	}
+}
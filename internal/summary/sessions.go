@@ -0,0 +1,135 @@
+package summary
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+// DefaultFocusSession is used when Options.FocusSession is unset.
+const DefaultFocusSession = 25 * time.Minute
+
+// maxDominant is how many apps and keywords a session lists.
+const maxDominant = 3
+
+// Session is a run of back-to-back events in one category.
+type Session struct {
+	CategoryName string
+	Start        time.Time
+	End          time.Time
+	Events       []storage.Event
+	Apps         []string // most frequently detected first
+	Keywords     []string // most frequently detected first
+}
+
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Away reports whether the session is time away from the machine rather
+// than activity.
+func (s Session) Away() bool {
+	return len(s.Events) > 0 && isAway(s.Events[0])
+}
+
+// Focus summarizes how fragmented the active (not away) time was.
+type Focus struct {
+	// Switches counts changes of category between back-to-back active
+	// sessions. SwitchesPerHour buckets them by the local hour they
+	// happened in.
+	Switches        int
+	SwitchesPerHour [24]int
+
+	// Score is the percentage of active time spent in sessions at least
+	// Options.FocusSession long, from 0 (all fragments) to 100.
+	Score float64
+}
+
+func isAway(e storage.Event) bool {
+	switch e.Status {
+	case "IDLE", "LOCKED", "SUSPENDED":
+		return true
+	}
+	return false
+}
+
+// Sessions merges spans into sessions. A new session starts whenever the
+// category or away state changes, or there is a gap between spans.
+func Sessions(spans []Span) []Session {
+	var sessions []Session
+	for _, s := range spans {
+		if n := len(sessions); n > 0 {
+			last := &sessions[n-1]
+			if last.CategoryName == s.CategoryName && last.Away() == isAway(s.Event) && last.End.Equal(s.Start) {
+				last.End = s.End
+				last.Events = append(last.Events, s.Event)
+				continue
+			}
+		}
+		sessions = append(sessions, Session{CategoryName: s.CategoryName, Start: s.Start, End: s.End, Events: []storage.Event{s.Event}})
+	}
+	for i := range sessions {
+		var apps, keywords []string
+		for _, e := range sessions[i].Events {
+			apps = append(apps, e.DetectedApps...)
+			keywords = append(keywords, e.DetectedKeywords...)
+		}
+		sessions[i].Apps = dominant(apps)
+		sessions[i].Keywords = dominant(keywords)
+	}
+	return sessions
+}
+
+// FocusOf measures sessions. Sessions of at least minSession count as
+// focused time.
+func FocusOf(sessions []Session, minSession time.Duration) Focus {
+	if minSession <= 0 {
+		minSession = DefaultFocusSession
+	}
+	var f Focus
+	var active, focused time.Duration
+	for i, s := range sessions {
+		if s.Away() {
+			continue
+		}
+		active += s.Duration()
+		if s.Duration() >= minSession {
+			focused += s.Duration()
+		}
+		if i > 0 {
+			prev := sessions[i-1]
+			if !prev.Away() && prev.End.Equal(s.Start) && prev.CategoryName != s.CategoryName {
+				f.Switches++
+				f.SwitchesPerHour[s.Start.Hour()]++
+			}
+		}
+	}
+	if active > 0 {
+		f.Score = float64(focused) / float64(active) * 100
+	}
+	return f
+}
+
+// dominant returns up to maxDominant values of items, most frequent first
+// and alphabetically among equals.
+func dominant(items []string) []string {
+	counts := map[string]int{}
+	for _, item := range items {
+		counts[item]++
+	}
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	if len(values) > maxDominant {
+		values = values[:maxDominant]
+	}
+	return values
+}
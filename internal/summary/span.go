@@ -0,0 +1,125 @@
+package summary
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aknow2/beholder/internal/storage"
+)
+
+// DefaultMaxSpan is used when Options.MaxSpan is unset. It matches the
+// default scheduler interval.
+const DefaultMaxSpan = 10 * time.Minute
+
+// uncategorized is the category name shown for events without one.
+const uncategorized = "未分類"
+
+// Span is the stretch of time an event stands for.
+type Span struct {
+	Event        storage.Event
+	CategoryName string
+	Start        time.Time
+	End          time.Time
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Block is where a Session ran, see DailySummary.LongestBlock.
+type Block struct {
+	CategoryName string
+	Start        time.Time
+	End          time.Time
+}
+
+func (b Block) Duration() time.Duration {
+	return b.End.Sub(b.Start)
+}
+
+// Spans turns events into spans in capture order. Each event lasts until
+// the next capture, but no longer than maxSpan, so gaps where nothing was
+// recorded (the machine was off, recording was paused) are not counted.
+// LOCKED and SUSPENDED events already carry their end in SpanEnd and keep it.
+// Spans are cut to p, and those outside it dropped.
+func Spans(events []storage.Event, maxSpan time.Duration, p Period) []Span {
+	if maxSpan <= 0 {
+		maxSpan = DefaultMaxSpan
+	}
+	sorted := make([]storage.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CapturedAt.Before(sorted[j].CapturedAt) })
+
+	spans := make([]Span, 0, len(sorted))
+	for i, e := range sorted {
+		start := e.CapturedAt.In(time.Local)
+		end := start.Add(maxSpan)
+		if !e.SpanEnd.IsZero() {
+			end = e.SpanEnd.In(time.Local)
+		}
+		if i+1 < len(sorted) {
+			if next := sorted[i+1].CapturedAt.In(time.Local); next.Before(end) {
+				end = next
+			}
+		}
+		if end.Before(start) {
+			end = start
+		}
+		if span, ok := (Span{Event: e, CategoryName: categoryOf(e), Start: start, End: end}).clip(p.Start, p.End); ok {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// clip cuts s to [from, to). ok is false when s lies outside the range.
+func (s Span) clip(from, to time.Time) (Span, bool) {
+	if !s.Start.Before(to) || (s.Start.Before(from) && !s.End.After(from)) {
+		return s, false
+	}
+	if s.Start.Before(from) {
+		s.Start = from
+	}
+	if s.End.After(to) {
+		s.End = to
+	}
+	return s, true
+}
+
+// continued reports whether s was cut at its start, i.e. its event was
+// captured before the range s was clipped to.
+func (s Span) continued() bool {
+	return s.Start.After(s.Event.CapturedAt)
+}
+
+func categoryOf(e storage.Event) string {
+	if e.CategoryName == "" {
+		return uncategorized
+	}
+	return e.CategoryName
+}
+
+// longestBlock returns the longest of sessions for which keep returns
+// true.
+func longestBlock(sessions []Session, keep func(Session) bool) Block {
+	var longest Block
+	for _, s := range sessions {
+		if keep(s) && s.Duration() > longest.Duration() {
+			longest = Block{CategoryName: s.CategoryName, Start: s.Start, End: s.End}
+		}
+	}
+	return longest
+}
+
+// addHours spreads span over the local hours it touches. s must lie within
+// one day.
+func addHours(hours *[24]time.Duration, s Span) {
+	for t := s.Start; t.Before(s.End); {
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		if next.After(s.End) {
+			next = s.End
+		}
+		hours[t.Hour()] += next.Sub(t)
+		t = next
+	}
+}
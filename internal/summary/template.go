@@ -0,0 +1,222 @@
+package summary
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+	"github.com/aknow2/beholder/internal/export"
+)
+
+//go:embed templates/builtin/*.tmpl
+var builtinFS embed.FS
+
+// TemplateData is what user templates are executed against. Fields common
+// to daily and multi-day reports are at the top level; Daily or Range holds
+// the full summary for the report kind.
+type TemplateData struct {
+	Title         string
+	Period        Period
+	TotalDuration time.Duration
+	TotalCount    int
+	Focus         Focus
+	Categories    []TemplateCategory // by time, longest first
+	Days          []*DailySummary    // one per day; a single entry for daily reports
+	Sessions      []Session          // in time order
+	Events        []export.Record    // in capture order, as in `beholder events --output json`
+
+	Daily *DailySummary // set for daily reports
+	Range *RangeSummary // set for --week, --month and --from/--to reports
+
+	Config    *config.Config
+	Generated time.Time
+}
+
+// TemplateCategory is a category's total over the report.
+type TemplateCategory struct {
+	ID       string // empty for categories not in the config
+	Name     string
+	Color    string
+	Duration time.Duration
+	Count    int
+	Share    float64 // percentage of TotalDuration
+}
+
+// DailyTemplateData builds the template data for a daily report.
+func DailyTemplateData(p Period, s *DailySummary, cfg *config.Config) *TemplateData {
+	d := &TemplateData{
+		Title:         "Daily Report - " + p.Start.Format("2006-01-02"),
+		Period:        p,
+		TotalDuration: s.TotalDuration,
+		TotalCount:    s.TotalCount,
+		Focus:         s.Focus,
+		Days:          []*DailySummary{s},
+		Sessions:      s.Sessions,
+		Daily:         s,
+		Config:        cfg,
+		Generated:     time.Now(),
+	}
+	for _, span := range s.Spans {
+		d.addEvent(span)
+	}
+	for _, cat := range s.Categories {
+		d.Categories = append(d.Categories, d.category(cat.CategoryName, cat.Duration, cat.Count, s.Share(cat)))
+	}
+	return d
+}
+
+// RangeTemplateData builds the template data for a multi-day report.
+func RangeTemplateData(r *RangeSummary, cfg *config.Config) *TemplateData {
+	d := &TemplateData{
+		Title:         r.title(),
+		Period:        r.Period,
+		TotalDuration: r.TotalDuration,
+		TotalCount:    r.TotalCount,
+		Focus:         r.Focus,
+		Days:          r.Days,
+		Range:         r,
+		Config:        cfg,
+		Generated:     time.Now(),
+	}
+	for _, day := range r.Days {
+		d.Sessions = append(d.Sessions, day.Sessions...)
+		for _, span := range day.Spans {
+			d.addEvent(span)
+		}
+	}
+	for _, cat := range r.Categories {
+		if cat.Duration > 0 {
+			d.Categories = append(d.Categories, d.category(cat.CategoryName, cat.Duration, cat.Count, r.Share(cat)))
+		}
+	}
+	return d
+}
+
+// addEvent adds the event of span, once for a span split across days.
+func (d *TemplateData) addEvent(span Span) {
+	if !span.continued() {
+		d.Events = append(d.Events, export.NewRecord(span.Event))
+	}
+}
+
+func (d *TemplateData) category(name string, dur time.Duration, count int, share float64) TemplateCategory {
+	c := TemplateCategory{Name: name, Color: otherColor, Duration: dur, Count: count, Share: share}
+	if d.Config != nil {
+		c.ID = d.categoryID(name)
+		c.Color = colorOf(CategoryColors(d.Config.Categories), name)
+	}
+	return c
+}
+
+func (d *TemplateData) categoryID(name string) string {
+	if d.Config == nil {
+		return ""
+	}
+	for _, c := range d.Config.Categories {
+		if c.Name == name {
+			return c.ID
+		}
+	}
+	return ""
+}
+
+// BuiltinTemplates lists the names of the templates shipped with beholder.
+func BuiltinTemplates() []string {
+	entries, _ := builtinFS.ReadDir("templates/builtin")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".tmpl"))
+	}
+	return names
+}
+
+// LoadTemplate reads a template from a file, or names a built-in template
+// when ref has no directory or extension, e.g. "standup".
+func LoadTemplate(ref string) (*template.Template, error) {
+	var data []byte
+	var err error
+	if !strings.ContainsAny(ref, `./\`) {
+		data, err = builtinFS.ReadFile(path.Join("templates/builtin", ref+".tmpl"))
+		if err != nil {
+			return nil, fmt.Errorf("unknown built-in template %q (available: %s)", ref, strings.Join(BuiltinTemplates(), ", "))
+		}
+	} else if data, err = os.ReadFile(ref); err != nil {
+		return nil, err
+	}
+
+	return template.New(ref).Option("missingkey=error").Funcs(templateFuncs(nil)).Parse(string(data))
+}
+
+// ExecuteTemplate renders t with d.
+func ExecuteTemplate(w io.Writer, t *template.Template, d *TemplateData) error {
+	return t.Funcs(templateFuncs(d)).Execute(w, d)
+}
+
+// templateFuncs are the helpers available to templates. d may be nil while
+// parsing, when only the names matter.
+func templateFuncs(d *TemplateData) template.FuncMap {
+	return template.FuncMap{
+		// duration renders a time.Duration as "2h 05m".
+		"duration": FormatDuration,
+		// hours renders a time.Duration as decimal hours, "2.08".
+		"hours": func(dur time.Duration) string { return fmt.Sprintf("%.2f", dur.Hours()) },
+		// percent renders part as a percentage of total, "42.5%".
+		"percent": func(part, total time.Duration) string {
+			if total <= 0 {
+				return "0.0%"
+			}
+			return fmt.Sprintf("%.1f%%", float64(part)/float64(total)*100)
+		},
+		// change renders a signed duration, "+1h 05m".
+		"change": formatChange,
+		// time formats t in local time with a Go layout.
+		"time":  func(layout string, t time.Time) string { return t.Local().Format(layout) },
+		"date":  func(t time.Time) string { return t.Local().Format("2006-01-02") },
+		"clock": func(t time.Time) string { return t.Local().Format("15:04") },
+		"join":  strings.Join,
+		// csv quotes s as a CSV field when needed.
+		"csv": func(s string) string {
+			var b strings.Builder
+			w := csv.NewWriter(&b)
+			_ = w.Write([]string{s})
+			w.Flush()
+			return strings.TrimSuffix(b.String(), "\n")
+		},
+		// longest returns the n longest non-away sessions in time order.
+		"longest": func(sessions []Session, n int) []Session {
+			var active []Session
+			for _, s := range sessions {
+				if !s.Away() {
+					active = append(active, s)
+				}
+			}
+			sort.SliceStable(active, func(i, j int) bool { return active[i].Duration() > active[j].Duration() })
+			if len(active) > n {
+				active = active[:n]
+			}
+			sort.SliceStable(active, func(i, j int) bool { return active[i].Start.Before(active[j].Start) })
+			return active
+		},
+		// categoryID and color look a category up by name in the config.
+		"categoryID": func(name string) string {
+			if d == nil {
+				return ""
+			}
+			return d.categoryID(name)
+		},
+		"color": func(name string) string {
+			if d == nil || d.Config == nil {
+				return otherColor
+			}
+			return colorOf(CategoryColors(d.Config.Categories), name)
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package summary
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aknow2/beholder/internal/config"
+)
+
+func renderTemplate(t *testing.T, ref string, d *TemplateData) string {
+	t.Helper()
+	tmpl, err := LoadTemplate(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ExecuteTemplate(&buf, tmpl, d); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	cfg := &config.Config{Categories: htmlCategories}
+	day := DaysPeriod(time.Date(2026, 1, 28, 0, 0, 0, 0, time.Local), time.Date(2026, 1, 28, 0, 0, 0, 0, time.Local))
+	daily := DailyTemplateData(day, GenerateWithOptions(day, sampleEvents(28), Options{MaxSpan: 10 * time.Minute}), cfg)
+
+	week, err := WeekPeriod("2026-W05", time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weekly := RangeTemplateData(GenerateRange(week, append(sampleEvents(26), sampleEvents(28)...), nil, Options{MaxSpan: 10 * time.Minute}), cfg)
+
+	tests := []struct {
+		name string
+		data *TemplateData
+		want []string
+	}{
+		{"standup", daily, []string{"Daily Report - 2026-01-28\n合計 50m / フォーカス 0 / 切り替え 1回\n", "- 実装: 20m (40.0%)\n", "- 09:00-09:20 実装 (20m) code, firefox"}},
+		{"timesheet", weekly, []string{"date,category_id,category,hours\n", "2026-01-26,implement,実装,0.33\n", "2026-01-28,,未分類,0.17"}},
+		{"sessions", weekly, []string{"Weekly Report - 2026-W05\n", "01-26 09:20-09:30      10m  会議 <&> [<script>zoom</script>]"}},
+	}
+	if got := strings.Join(BuiltinTemplates(), ","); got != "sessions,standup,timesheet" {
+		t.Errorf("BuiltinTemplates() = %s", got)
+	}
+	for _, tt := range tests {
+		got := renderTemplate(t, tt.name, tt.data)
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: missing %q in:\n%s", tt.name, want, got)
+			}
+		}
+	}
+}
+
+func TestUserTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	src := `{{range .Categories}}{{.ID}}={{hours .Duration}}h {{color .Name}}{{"\n"}}{{end}}{{with index .Events 4}}{{.Status}} at {{.CapturedAt}}{{"\n"}}{{end}}{{with .Daily}}first={{clock .FirstAt}}{{end}}`
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Categories: htmlCategories}
+	day := DaysPeriod(time.Date(2026, 1, 28, 0, 0, 0, 0, time.Local), time.Date(2026, 1, 28, 0, 0, 0, 0, time.Local))
+	got := renderTemplate(t, path, DailyTemplateData(day, GenerateWithOptions(day, sampleEvents(28), Options{MaxSpan: 10 * time.Minute}), cfg))
+	failedAt := time.Date(2026, 1, 28, 10, 15, 0, 0, time.Local).UTC().Format(time.RFC3339)
+	if !strings.HasPrefix(got, "implement=0.33h #4e79a7\nmeeting=0.17h #f28e2b\n") || !strings.HasSuffix(got, "FAILED at "+failedAt+"\nfirst=09:00") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+
+	if _, err := LoadTemplate("weekly-digest"); err == nil || !strings.Contains(err.Error(), "standup") {
+		t.Errorf("unknown built-in should list the available ones, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{{.Nope}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadTemplate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ExecuteTemplate(&bytes.Buffer{}, tmpl, &TemplateData{}); err == nil {
+		t.Error("unknown field should error")
+	}
+}
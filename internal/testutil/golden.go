@@ -0,0 +1,30 @@
+// Package testutil holds helpers shared by tests.
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// CheckGolden compares got with testdata/name, rewriting the file first
+// when the test runs with -update.
+func CheckGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch (run go test -update to accept):\n--- got\n%s\n--- want\n%s", name, got, want)
+	}
+}
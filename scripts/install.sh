@@ -249,6 +249,7 @@ main() {
     echo ""
     info "Verify installation: beholder --version"
     info "Get started: beholder help"
+    info "Start recording on login: beholder service install"
     echo ""
 }
 
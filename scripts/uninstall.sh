@@ -74,6 +74,9 @@ remove_binary() {
         return
     fi
     
+    # Stop and unregister the login service before the binary disappears
+    "$INSTALLATION_PATH" service uninstall >/dev/null 2>&1 || true
+
     info "Removing binary from $INSTALLATION_PATH..."
     rm -f "$INSTALLATION_PATH"
     success "Binary removed"